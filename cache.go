@@ -0,0 +1,144 @@
+package gopathignore
+
+import (
+	"container/list"
+	"strings"
+	"sync"
+
+	"github.com/vbhat161/go-path-ignore/match"
+)
+
+// cacheKey tells apart the ways a path can be matched: by Match2, which infers
+// directories from a trailing slash, and by MatchEntry for files and directories.
+type cacheKey struct {
+	path string
+	kind cacheKind
+}
+
+type cacheKind uint8
+
+const (
+	kindPath cacheKind = iota
+	kindFile
+	kindDir
+)
+
+func entryKey(path string, isDir bool) cacheKey {
+	if isDir {
+		return cacheKey{path: path, kind: kindDir}
+	}
+	return cacheKey{path: path, kind: kindFile}
+}
+
+// cache is a bounded LRU cache of match decisions. Errors are not cached.
+type cache struct {
+	mu    sync.Mutex
+	size  int
+	ll    *list.List // of *cacheEntry, most recently used first
+	items map[cacheKey]*list.Element
+}
+
+type cacheEntry struct {
+	key cacheKey
+	res match.MatchInfo
+}
+
+func newCache(size int) *cache {
+	return &cache{size: size, ll: list.New(), items: make(map[cacheKey]*list.Element, size)}
+}
+
+// lookup returns the cached decision for key. Failing that, if a parent directory
+// of the path is known to be excluded by a gitignore rule, it returns that
+// decision with parent set: like git, which does not look into excluded
+// directories, the rule applies to everything below the directory, unless a
+// strategy evaluated before gitignore matches the path itself.
+func (c *cache) lookup(key cacheKey) (res match.MatchInfo, parent, ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if res, ok := c.get(key); ok {
+		return res, false, true
+	}
+
+	path := strings.TrimSuffix(key.path, "/")
+	for i := 0; i < len(path); i++ {
+		if path[i] != '/' {
+			continue
+		}
+		for _, dir := range []cacheKey{{path: path[:i], kind: kindDir}, {path: path[:i+1], kind: kindPath}} {
+			if res, ok := c.get(dir); ok && res.Ok() && res.Type() == match.GitIgnore {
+				return res, true, true
+			}
+		}
+	}
+	return nil, false, false
+}
+
+func (c *cache) get(key cacheKey) (match.MatchInfo, bool) {
+	e, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+	c.ll.MoveToFront(e)
+	return e.Value.(*cacheEntry).res, true
+}
+
+func (c *cache) add(key cacheKey, res match.MatchInfo) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if e, ok := c.items[key]; ok {
+		e.Value.(*cacheEntry).res = res
+		c.ll.MoveToFront(e)
+		return
+	}
+	c.items[key] = c.ll.PushFront(&cacheEntry{key: key, res: res})
+	if c.ll.Len() > c.size {
+		oldest := c.ll.Back()
+		c.ll.Remove(oldest)
+		delete(c.items, oldest.Value.(*cacheEntry).key)
+	}
+}
+
+// cached returns the cached decision for key, or calls matchFn with the matchers
+// and caches its result. Without a cache it only calls matchFn. A decision
+// inherited from an excluded parent directory is checked against the strategies
+// evaluated before gitignore, whose rules still apply to the path.
+func (pi *PathIgnore) cached(key cacheKey, matchFn func([]match.PathMatcher) (match.MatchInfo, error)) (match.MatchInfo, error) {
+	if pi.cache == nil {
+		return matchFn(pi.matchers)
+	}
+	res, parent, ok := pi.cache.lookup(key)
+	if ok && !parent {
+		return res, nil
+	}
+	if ok {
+		earlier, err := matchFn(pi.beforeGitIgnore())
+		if err != nil {
+			return nil, err
+		}
+		if earlier.Ok() {
+			res = earlier
+		}
+		pi.cache.add(key, res)
+		return res, nil
+	}
+
+	res, err := matchFn(pi.matchers)
+	if err != nil {
+		return nil, err
+	}
+	pi.cache.add(key, res)
+	return res, nil
+}
+
+// beforeGitIgnore returns the matchers of the strategies evaluated before the
+// gitignore strategy.
+func (pi *PathIgnore) beforeGitIgnore() []match.PathMatcher {
+	for i, m := range pi.strategies {
+		if m.Type() == match.GitIgnore {
+			return pi.strategies[:i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,152 @@
+package gopathignore
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vbhat161/go-path-ignore/match"
+	"github.com/vbhat161/go-path-ignore/match/gitignore"
+	"github.com/vbhat161/go-path-ignore/match/regex"
+)
+
+// countingMatcher counts the paths given to the wrapped matcher.
+type countingMatcher struct {
+	match.PathMatcher
+	calls map[string]int
+}
+
+func (m *countingMatcher) Match2(ctx context.Context, path string) (match.MatchInfo, error) {
+	m.calls[path]++
+	return m.PathMatcher.Match2(ctx, path)
+}
+
+func (m *countingMatcher) MatchEntry(ctx context.Context, path string, isDir bool) (match.MatchInfo, error) {
+	m.calls[path]++
+	return m.PathMatcher.MatchEntry(ctx, path, isDir)
+}
+
+func newCountingPathIgnore(t *testing.T, cacheSize int) (*PathIgnore, *countingMatcher, *countingMatcher) {
+	t.Helper()
+	re, err := regex.NewMatcher(regex.Options{Patterns: []string{`\.tmp$`}})
+	require.NoError(t, err)
+	gi, err := gitignore.NewMatcher(gitignore.Options{Patterns: []string{"node_modules/", "*.log", "!keep.log"}})
+	require.NoError(t, err)
+
+	reCounter := &countingMatcher{PathMatcher: re, calls: map[string]int{}}
+	giCounter := &countingMatcher{PathMatcher: gi, calls: map[string]int{}}
+	matchers := []match.PathMatcher{reCounter, giCounter}
+	return &PathIgnore{matchers: matchers, strategies: matchers, workers: 1, cache: newCache(cacheSize)},
+		reCounter, giCounter
+}
+
+func TestCache(t *testing.T) {
+	ctx := context.Background()
+	pi, re, gi := newCountingPathIgnore(t, 2)
+
+	for range 3 {
+		res, err := pi.Match2(ctx, "a.log")
+		require.NoError(t, err)
+		require.True(t, res.Ok())
+	}
+	require.Equal(t, 1, gi.calls["a.log"])
+
+	// Files, directories and Match2 paths are cached separately.
+	_, err := pi.MatchEntry(ctx, "a.log", false)
+	require.NoError(t, err)
+	require.Equal(t, 2, gi.calls["a.log"])
+
+	// The least recently used decision is evicted.
+	_, err = pi.Match2(ctx, "b.log")
+	require.NoError(t, err)
+	_, err = pi.Match2(ctx, "a.log")
+	require.NoError(t, err)
+	require.Equal(t, 3, gi.calls["a.log"])
+
+	results, err := pi.MatchAll(ctx, []string{"a.log", "c.log", "c.log"})
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+	require.Equal(t, 3, gi.calls["a.log"])
+	require.Equal(t, 1, gi.calls["c.log"])
+	require.Equal(t, 1, re.calls["c.log"])
+}
+
+func TestCacheParentDir(t *testing.T) {
+	ctx := context.Background()
+	pi, re, gi := newCountingPathIgnore(t, 100)
+
+	res, err := pi.MatchEntry(ctx, "node_modules", true)
+	require.NoError(t, err)
+	require.True(t, res.Ok())
+
+	res, err = pi.MatchEntry(ctx, "node_modules/pkg/index.js", false)
+	require.NoError(t, err)
+	require.True(t, res.Ok())
+	require.Equal(t, "node_modules/", res.Src())
+	require.Zero(t, gi.calls["node_modules/pkg/index.js"])
+	// The regex rules, evaluated before the gitignore rules, still apply.
+	require.Equal(t, 1, re.calls["node_modules/pkg/index.js"])
+
+	res, err = pi.MatchEntry(ctx, "node_modules/pkg/cache.tmp", false)
+	require.NoError(t, err)
+	require.Equal(t, match.Regex, res.Type())
+	require.Zero(t, gi.calls["node_modules/pkg/cache.tmp"])
+
+	res, err = pi.Match2(ctx, "node_modules/keep.log")
+	require.NoError(t, err)
+	require.True(t, res.Ok())
+	require.Zero(t, gi.calls["node_modules/keep.log"])
+
+	// Only directories excluded by gitignore rules cover their contents.
+	res, err = pi.MatchEntry(ctx, "cache.tmp", true)
+	require.NoError(t, err)
+	require.True(t, res.Ok())
+	res, err = pi.MatchEntry(ctx, "cache.tmp/data", false)
+	require.NoError(t, err)
+	require.False(t, res.Ok())
+
+	res, err = pi.MatchEntry(ctx, "src", true)
+	require.NoError(t, err)
+	require.False(t, res.Ok())
+	_, err = pi.MatchEntry(ctx, "src/keep.log", false)
+	require.NoError(t, err)
+	require.Equal(t, 1, gi.calls["src/keep.log"])
+}
+
+func TestCacheMatchesUncached(t *testing.T) {
+	for _, singlePass := range []bool{false, true} {
+		opts := Options{
+			Regex:      &regex.Options{Patterns: []string{`\.tmp$`}},
+			GitIgnore:  &gitignore.Options{Patterns: []string{"node_modules/", "build/", "*.log"}},
+			SinglePass: singlePass,
+		}
+		uncached, err := New(opts)
+		require.NoError(t, err)
+		opts.CacheSize = 100
+		cached, err := New(opts)
+		require.NoError(t, err)
+
+		ctx := context.Background()
+		// Directories first, so that their children inherit the cached decision.
+		for _, path := range []string{
+			"node_modules/", "node_modules/a.tmp", "node_modules/b/c.js", "node_modules/b/c.tmp",
+			"build/", "build/x.tmp/", "build/x.tmp/y", "build/a.log", "a.tmp",
+		} {
+			want, err := uncached.Match2(ctx, path)
+			require.NoError(t, err)
+			for range 2 {
+				got, err := cached.Match2(ctx, path)
+				require.NoError(t, err)
+				require.Equal(t, want.Ok(), got.Ok(), "single pass %v, path %q", singlePass, path)
+				require.Equal(t, want.Type(), got.Type(), "single pass %v, path %q", singlePass, path)
+				require.Equal(t, want.Source(), got.Source(), "single pass %v, path %q", singlePass, path)
+			}
+
+			wantExp, err := uncached.Explain(ctx, path)
+			require.NoError(t, err)
+			gotExp, err := cached.Explain(ctx, path)
+			require.NoError(t, err)
+			require.Equal(t, wantExp.Hits, gotExp.Hits, path)
+		}
+	}
+}
@@ -0,0 +1,227 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	gopathignore "github.com/vbhat161/go-path-ignore"
+	"github.com/vbhat161/go-path-ignore/match"
+)
+
+// checkIgnore implements the check-ignore command, a drop-in for git check-ignore
+// that also works outside git repositories. It exits with 0 if one or more paths
+// are ignored, 1 if none is and 128 on errors.
+func checkIgnore(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("check-ignore", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "usage: pathignore check-ignore [flags] <path>...")
+		fmt.Fprintln(stderr, "   or: pathignore check-ignore [flags] --stdin")
+		fs.PrintDefaults()
+	}
+
+	var (
+		rules       ruleFlags
+		readStdin   bool
+		nul         bool
+		verbose     bool
+		nonMatching bool
+		quiet       bool
+	)
+	rules.register(fs)
+	fs.BoolVar(&readStdin, "stdin", false, "read paths from stdin, one per line")
+	fs.BoolVar(&nul, "z", false, "paths are NUL separated on stdin and in the output")
+	fs.BoolVar(&verbose, "v", false, "print the matching pattern along with each path")
+	fs.BoolVar(&verbose, "verbose", false, "same as -v")
+	fs.BoolVar(&nonMatching, "n", false, "also print paths that match no pattern, requires -v")
+	fs.BoolVar(&nonMatching, "non-matching", false, "same as -n")
+	fs.BoolVar(&quiet, "q", false, "print nothing, only set the exit status")
+	fs.BoolVar(&quiet, "quiet", false, "same as -q")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+		return exitFatal
+	}
+
+	paths := fs.Args()
+	switch {
+	case readStdin && len(paths) > 0:
+		return fatal(stderr, errors.New("cannot specify pathnames with --stdin"))
+	case !readStdin && len(paths) == 0:
+		return fatal(stderr, errors.New("no path specified"))
+	case nul && !readStdin:
+		return fatal(stderr, errors.New("-z only makes sense with --stdin"))
+	case nonMatching && !verbose:
+		return fatal(stderr, errors.New("--non-matching is only valid with --verbose"))
+	case quiet && verbose:
+		return fatal(stderr, errors.New("cannot have both --quiet and --verbose"))
+	case quiet && len(paths) > 1:
+		return fatal(stderr, errors.New("--quiet is only valid with a single pathname"))
+	}
+
+	opts, root, err := rules.options(treeRoot("."))
+	if err != nil {
+		return fatal(stderr, err)
+	}
+	pi, err := gopathignore.New(opts)
+	if err != nil {
+		return fatal(stderr, err)
+	}
+
+	out := bufio.NewWriter(stdout)
+	defer out.Flush()
+	c := &checker{pi: pi, root: root, out: out, nul: nul, verbose: verbose, nonMatching: nonMatching, quiet: quiet}
+
+	if readStdin {
+		s := bufio.NewScanner(stdin)
+		if nul {
+			s.Split(splitNUL)
+		}
+		for s.Scan() {
+			if err := c.check(s.Text()); err != nil {
+				return fatal(stderr, err)
+			}
+			// Callers may wait for the answer before sending the next path.
+			if err := out.Flush(); err != nil {
+				return fatal(stderr, err)
+			}
+		}
+		if err := s.Err(); err != nil {
+			return fatal(stderr, err)
+		}
+	} else {
+		for _, path := range paths {
+			if err := c.check(path); err != nil {
+				return fatal(stderr, err)
+			}
+		}
+	}
+
+	if err := out.Flush(); err != nil {
+		return fatal(stderr, err)
+	}
+	if c.ignored == 0 {
+		return exitNone
+	}
+	return exitOK
+}
+
+type checker struct {
+	pi      *gopathignore.PathIgnore
+	root    string
+	out     *bufio.Writer
+	ignored int
+
+	nul, verbose, nonMatching, quiet bool
+}
+
+// check reports whether path is ignored. In verbose mode, like git, a path
+// matching a negated pattern is reported with that pattern and counts as
+// matched.
+func (c *checker) check(path string) error {
+	rel, err := relPath(c.root, path)
+	if err != nil {
+		return err
+	}
+	if !strings.HasSuffix(rel, "/") {
+		if info, err := os.Stat(filepath.Join(c.root, filepath.FromSlash(rel))); err == nil && info.IsDir() {
+			rel += "/"
+		}
+	}
+
+	res, err := c.pi.Match2(context.Background(), rel)
+	if err != nil {
+		return err
+	}
+	src, matched := res.Source(), res.Ok()
+	if matched {
+		src.File = c.ruleFile(src, res.Type())
+	}
+	if !matched && c.verbose {
+		exp, err := c.pi.Explain(context.Background(), rel)
+		if err != nil {
+			return err
+		}
+		// Without a match, only a negation can be decisive.
+		for _, h := range exp.Hits {
+			if h.Decisive {
+				src, matched = h.Source, true
+				src.File = c.ruleFile(src, h.Type)
+			}
+		}
+	}
+
+	if matched {
+		c.ignored++
+	}
+	if c.quiet || (!matched && !c.nonMatching) {
+		return nil
+	}
+	c.print(path, src, matched)
+	return nil
+}
+
+// ruleFile returns the file of a rule as git check-ignore prints it: relative to
+// the root for the files under it, or the strategy of patterns given as flags.
+func (c *checker) ruleFile(src match.Source, typ match.Type) string {
+	if src.File == "" {
+		return typ.String()
+	}
+	if c.root == "." || filepath.IsAbs(src.File) != filepath.IsAbs(c.root) {
+		return src.File
+	}
+	rel, err := filepath.Rel(c.root, src.File)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return src.File
+	}
+	return rel
+}
+
+// print writes a result line in the format of git check-ignore.
+func (c *checker) print(path string, src match.Source, matched bool) {
+	if !c.verbose {
+		c.out.WriteString(path)
+		c.out.WriteByte(c.sep())
+		return
+	}
+
+	var file, line, pattern string
+	if matched {
+		file, line, pattern = src.File, fmt.Sprint(src.Line), src.Pattern
+	}
+	if c.nul {
+		for _, field := range []string{file, line, pattern, path} {
+			c.out.WriteString(field)
+			c.out.WriteByte(0)
+		}
+		return
+	}
+	fmt.Fprintf(c.out, "%s:%s:%s\t%s\n", file, line, pattern, path)
+}
+
+func (c *checker) sep() byte {
+	if c.nul {
+		return 0
+	}
+	return '\n'
+}
+
+// splitNUL is a bufio.SplitFunc for NUL terminated records.
+func splitNUL(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	if i := bytes.IndexByte(data, 0); i >= 0 {
+		return i + 1, data[:i], nil
+	}
+	if atEOF && len(data) > 0 {
+		return len(data), data, nil
+	}
+	return 0, nil, nil
+}
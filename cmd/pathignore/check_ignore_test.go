@@ -0,0 +1,142 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// newTree creates files under a temporary directory and makes it the working
+// directory. Names ending with a slash are created as directories.
+func newTree(t *testing.T, files map[string]string) string {
+	t.Helper()
+	root := t.TempDir()
+	for name, data := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if strings.HasSuffix(name, "/") {
+			require.NoError(t, os.MkdirAll(path, 0o755))
+			continue
+		}
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(data), 0o644))
+	}
+	t.Chdir(root)
+	return root
+}
+
+func runCmd(stdin string, args ...string) (code int, stdout, stderr string) {
+	var out, errOut bytes.Buffer
+	code = run(args, strings.NewReader(stdin), &out, &errOut)
+	return code, out.String(), errOut.String()
+}
+
+func TestCheckIgnore(t *testing.T) {
+	newTree(t, map[string]string{
+		".gitignore":     "*.log\n!keep.log\nbuild/\n",
+		"sub/.gitignore": "x\n",
+		"build/":         "",
+	})
+
+	code, out, _ := runCmd("", "check-ignore", "a.log", "keep.log", "build", "main.go", "sub/x")
+	require.Equal(t, exitOK, code)
+	require.Equal(t, "a.log\nbuild\nsub/x\n", out)
+
+	code, out, _ = runCmd("", "check-ignore", "-v", "-n", "a.log", "keep.log", "build", "main.go", "sub/x")
+	require.Equal(t, exitOK, code)
+	require.Equal(t, ".gitignore:1:*.log\ta.log\n"+
+		".gitignore:2:!keep.log\tkeep.log\n"+
+		".gitignore:3:build/\tbuild\n"+
+		"::\tmain.go\n"+
+		filepath.Join("sub", ".gitignore")+":1:x\tsub/x\n", out)
+
+	code, out, _ = runCmd("", "check-ignore", "main.go", "keep.log")
+	require.Equal(t, exitNone, code)
+	require.Empty(t, out)
+
+	// A negation counts as a match in verbose mode, as for git.
+	code, _, _ = runCmd("", "check-ignore", "-v", "keep.log")
+	require.Equal(t, exitOK, code)
+
+	code, out, _ = runCmd("a.log\x00main.go\x00", "check-ignore", "--stdin", "-z", "-v", "-n")
+	require.Equal(t, exitOK, code)
+	require.Equal(t, ".gitignore\x001\x00*.log\x00a.log\x00\x00\x00\x00main.go\x00", out)
+
+	code, out, _ = runCmd("a.log\nmain.go\n", "check-ignore", "--stdin")
+	require.Equal(t, exitOK, code)
+	require.Equal(t, "a.log\n", out)
+
+	code, out, _ = runCmd("", "check-ignore", "-q", "a.log")
+	require.Equal(t, exitOK, code)
+	require.Empty(t, out)
+
+	code, out, _ = runCmd("main.go\na.log\n", "check-ignore", "-q", "--stdin")
+	require.Equal(t, exitOK, code)
+	require.Empty(t, out)
+
+	code, out, _ = runCmd("main.go\x00keep.log\x00", "check-ignore", "-q", "--stdin", "-z")
+	require.Equal(t, exitNone, code)
+	require.Empty(t, out)
+}
+
+func TestCheckIgnoreWorkTree(t *testing.T) {
+	root := newTree(t, map[string]string{
+		".git/info/exclude": "secret\n",
+		".gitignore":        "*.log\n",
+		"sub/.gitignore":    "x\n",
+	})
+	t.Chdir(filepath.Join(root, "sub"))
+
+	// Like git check-ignore, the rules of the whole work tree apply, and their
+	// files are relative to its top.
+	code, out, _ := runCmd("", "check-ignore", "-v", "a.log", "x", "secret", "../b.log", "main.go")
+	require.Equal(t, exitOK, code)
+	require.Equal(t, ".gitignore:1:*.log\ta.log\n"+
+		filepath.Join("sub", ".gitignore")+":1:x\tx\n"+
+		filepath.Join(".git", "info", "exclude")+":1:secret\tsecret\n"+
+		".gitignore:1:*.log\t../b.log\n", out)
+
+	code, _, _ = runCmd("", "check-ignore", "../sub/main.go")
+	require.Equal(t, exitNone, code)
+}
+
+func TestCheckIgnoreFlags(t *testing.T) {
+	newTree(t, map[string]string{
+		"rules.yaml": "glob:\n  patterns: ['*.tmp']\nregex:\n  patterns: ['^vendor/']\n",
+	})
+
+	code, out, _ := runCmd("", "check-ignore", "-v", "--glob", "*.tmp", "--regex", `\.bak$`, "--exclude", "dist/", "a.tmp", "a.bak", "dist/", "a.go")
+	require.Equal(t, exitOK, code)
+	require.Equal(t, "glob:1:*.tmp\ta.tmp\nregex:1:\\.bak$\ta.bak\ngitignore:1:dist/\tdist/\n", out)
+
+	code, out, _ = runCmd("", "check-ignore", "--config", "rules.yaml", "vendor/x.go", "a.tmp", "a.go")
+	require.Equal(t, exitOK, code)
+	require.Equal(t, "vendor/x.go\na.tmp\n", out)
+
+	code, _, stderr := runCmd("", "check-ignore", "--config", "missing.yaml", "a.go")
+	require.Equal(t, exitFatal, code)
+	require.Contains(t, stderr, "fatal: ")
+}
+
+func TestCheckIgnoreUsageErrors(t *testing.T) {
+	newTree(t, nil)
+
+	for _, args := range [][]string{
+		{"check-ignore"},
+		{"check-ignore", "--stdin", "a"},
+		{"check-ignore", "-z", "a"},
+		{"check-ignore", "-n", "a"},
+		{"check-ignore", "-q", "-v", "a"},
+		{"check-ignore", "-q", "a", "b"},
+		{"check-ignore", "-q"},
+		{"check-ignore", "--unknown", "a"},
+		{"check-ignore", "../outside"},
+		{"no-such-command"},
+	} {
+		code, _, _ := runCmd("", args...)
+		require.Equal(t, exitFatal, code, args)
+	}
+}
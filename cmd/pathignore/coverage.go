@@ -0,0 +1,216 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	gopathignore "github.com/vbhat161/go-path-ignore"
+	"github.com/vbhat161/go-path-ignore/match"
+)
+
+// coverage implements the coverage command, which evaluates the rules against
+// the entries of a directory or a list of paths and reports how often each rule
+// matched, the rules that never matched and those an earlier rule covers. It
+// exits with 1 if a rule is unused or covered, 0 otherwise.
+func coverage(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("coverage", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "usage: pathignore coverage [flags] [dir]")
+		fmt.Fprintln(stderr, "   or: pathignore coverage [flags] --stdin")
+		fs.PrintDefaults()
+	}
+
+	var (
+		rules     ruleFlags
+		readStdin bool
+		nul       bool
+		format    string
+	)
+	rules.register(fs)
+	fs.BoolVar(&readStdin, "stdin", false, "evaluate the paths read from stdin, one per line, instead of a directory")
+	fs.BoolVar(&nul, "z", false, "paths are NUL separated on stdin")
+	fs.StringVar(&format, "format", "text", "output `format`: text or json")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+		return exitFatal
+	}
+
+	dir := "."
+	switch {
+	case readStdin && fs.NArg() > 0:
+		return fatal(stderr, errors.New("cannot specify a directory with --stdin"))
+	case fs.NArg() == 1:
+		dir = fs.Arg(0)
+	case fs.NArg() > 1:
+		return fatal(stderr, errors.New("too many directories"))
+	}
+	switch {
+	case nul && !readStdin:
+		return fatal(stderr, errors.New("-z only makes sense with --stdin"))
+	case format != "text" && format != "json":
+		return fatal(stderr, fmt.Errorf("unknown format %q", format))
+	}
+
+	defaultRoot := treeRoot(dir)
+	opts, root, err := rules.options(defaultRoot)
+	if err != nil {
+		return fatal(stderr, err)
+	}
+	if fs.NArg() == 0 && root != defaultRoot {
+		dir = root
+	}
+	pi, err := gopathignore.New(opts)
+	if err != nil {
+		return fatal(stderr, err)
+	}
+
+	ctx := context.Background()
+	c := pi.NewCoverage()
+	if readStdin {
+		err = scanPaths(stdin, nul, root, func(path string) error {
+			_, err := c.Add(ctx, path)
+			return err
+		})
+	} else {
+		var start string
+		if start, err = relPath(root, dir); err == nil {
+			err = c.AddFS(ctx, os.DirFS(root), strings.TrimSuffix(start, "/"), warn(stderr))
+		}
+	}
+	if err != nil {
+		return fatal(stderr, err)
+	}
+
+	r := c.Report()
+	if format == "json" {
+		err = writeCoverageJSON(stdout, r)
+	} else {
+		err = writeCoverageText(stdout, r)
+	}
+	if err != nil {
+		return fatal(stderr, err)
+	}
+	if len(r.Unused()) > 0 || len(r.Covered()) > 0 {
+		return exitNone
+	}
+	return exitOK
+}
+
+// scanPaths calls add with the paths read from r, one per line or NUL separated,
+// relative to root. Like for check-ignore, a path naming an existing directory
+// is one.
+func scanPaths(r io.Reader, nul bool, root string, add func(path string) error) error {
+	s := bufio.NewScanner(r)
+	if nul {
+		s.Split(splitNUL)
+	}
+	for s.Scan() {
+		if s.Text() == "" {
+			continue
+		}
+		rel, err := relPath(root, s.Text())
+		if err != nil {
+			return err
+		}
+		if !strings.HasSuffix(rel, "/") {
+			if info, err := os.Stat(filepath.Join(root, filepath.FromSlash(rel))); err == nil && info.IsDir() {
+				rel += "/"
+			}
+		}
+		if err := add(rel); err != nil {
+			return err
+		}
+	}
+	return s.Err()
+}
+
+// ruleLocation returns file:line of a rule, with the strategy name for rules
+// given as patterns.
+func ruleLocation(typ match.Type, src match.Source) string {
+	file := src.File
+	if file == "" {
+		file = typ.String()
+	}
+	return fmt.Sprintf("%s:%d", file, src.Line)
+}
+
+func writeCoverageText(w io.Writer, r gopathignore.CoverageReport) error {
+	var buf strings.Builder
+	tw := tabwriter.NewWriter(&buf, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "HITS\tDECISIVE\tRULE\t")
+	for _, rc := range r.Rules {
+		fmt.Fprintf(tw, "%d\t%d\t%s:%s\t", rc.Hits, rc.Decisive, ruleLocation(rc.Type, rc.Source), rc.Source.Pattern)
+		switch {
+		case rc.Hits == 0:
+			fmt.Fprint(tw, "unused")
+		case rc.CoveredBy >= 0:
+			o := r.Rules[rc.CoveredBy]
+			fmt.Fprintf(tw, "covered by %s:%s", ruleLocation(o.Type, o.Source), o.Source.Pattern)
+		}
+		fmt.Fprintln(tw)
+	}
+	tw.Flush()
+
+	// The padding of the rule column trails the lines of rules without a note.
+	out := bufio.NewWriter(w)
+	for line := range strings.Lines(buf.String()) {
+		out.WriteString(strings.TrimRight(line, " \n"))
+		out.WriteByte('\n')
+	}
+	fmt.Fprintf(out, "\n%d paths, %d rules, %d unused, %d covered\n", r.Paths, len(r.Rules), len(r.Unused()), len(r.Covered()))
+	return out.Flush()
+}
+
+// coverageRule is a rule in the json format.
+type coverageRule struct {
+	Type      string        `json:"type"`
+	File      string        `json:"file,omitempty"`
+	Line      int           `json:"line"`
+	Pattern   string        `json:"pattern"`
+	Negate    bool          `json:"negate,omitempty"`
+	Hits      int           `json:"hits"`
+	Decisive  int           `json:"decisive"`
+	Unused    bool          `json:"unused,omitempty"`
+	CoveredBy *coverageRule `json:"covered_by,omitempty"`
+}
+
+func writeCoverageJSON(w io.Writer, r gopathignore.CoverageReport) error {
+	rule := func(rc gopathignore.RuleCoverage) coverageRule {
+		return coverageRule{
+			Type:    rc.Type.String(),
+			File:    rc.Source.File,
+			Line:    rc.Source.Line,
+			Pattern: rc.Source.Pattern,
+			Negate:  rc.Source.Negate,
+		}
+	}
+	rules := make([]coverageRule, 0, len(r.Rules))
+	for _, rc := range r.Rules {
+		cr := rule(rc)
+		cr.Hits, cr.Decisive, cr.Unused = rc.Hits, rc.Decisive, rc.Hits == 0
+		if rc.CoveredBy >= 0 {
+			o := rule(r.Rules[rc.CoveredBy])
+			cr.CoveredBy = &o
+		}
+		rules = append(rules, cr)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(struct {
+		Paths int            `json:"paths"`
+		Rules []coverageRule `json:"rules"`
+	}{r.Paths, rules})
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCoverage(t *testing.T) {
+	newTree(t, map[string]string{
+		".gitignore":  "*.log\n!keep.log\ndebug.log\nbuild/\nunused\n",
+		".git/x.log":  "",
+		"a.log":       "",
+		"keep.log":    "",
+		"debug.log":   "",
+		"build/x.log": "",
+		"src/main.go": "",
+	})
+
+	code, out, _ := runCmd("", "coverage")
+	require.Equal(t, exitNone, code)
+	require.Equal(t, "HITS  DECISIVE  RULE\n"+
+		"3     1         .gitignore:1:*.log\n"+
+		"1     1         .gitignore:2:!keep.log\n"+
+		"1     1         .gitignore:3:debug.log  covered by .gitignore:1:*.log\n"+
+		"1     1         .gitignore:4:build/\n"+
+		"0     0         .gitignore:5:unused     unused\n"+
+		"\n7 paths, 5 rules, 1 unused, 1 covered\n", out)
+
+	// The rules of the current directory apply to a subdirectory.
+	code, out, _ = runCmd("", "coverage", "build")
+	require.Equal(t, exitNone, code)
+	require.Contains(t, out, "\n1 paths, 5 rules, 4 unused, 0 covered\n")
+
+	code, out, _ = runCmd("a.log\nbuild\nsrc/main.go\n", "coverage", "--stdin", "--exclude", "*.log", "--exclude", "build/")
+	require.Equal(t, exitOK, code)
+	require.Contains(t, out, "3 paths, 2 rules, 0 unused, 0 covered\n")
+
+	code, out, _ = runCmd("a.log\x00keep.log\x00", "coverage", "--stdin", "-z", "--format", "json", "--exclude", "*.log", "--glob", "a.*")
+	require.Equal(t, exitNone, code)
+	var report struct {
+		Paths int
+		Rules []coverageRule
+	}
+	require.Contains(t, out, `"covered_by": {`)
+	require.NoError(t, json.Unmarshal([]byte(out), &report))
+	require.Equal(t, 2, report.Paths)
+	require.Equal(t, []coverageRule{
+		{Type: "gitignore", Line: 1, Pattern: "*.log", Hits: 2, Decisive: 2},
+		{Type: "glob", Line: 1, Pattern: "a.*", Hits: 1, CoveredBy: &coverageRule{Type: "gitignore", Line: 1, Pattern: "*.log"}},
+	}, report.Rules)
+
+	for _, args := range [][]string{
+		{"coverage", "a", "b"},
+		{"coverage", "--stdin", "src"},
+		{"coverage", "-z"},
+		{"coverage", "--format", "xml"},
+		{"coverage", "missing"},
+	} {
+		code, _, _ := runCmd("", args...)
+		require.Equal(t, exitFatal, code, args)
+	}
+}
@@ -0,0 +1,215 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	gopathignore "github.com/vbhat161/go-path-ignore"
+	"github.com/vbhat161/go-path-ignore/match"
+	"github.com/vbhat161/go-path-ignore/match/gitignore"
+)
+
+// diff implements the diff command, which compares two rule sets on the entries
+// of a directory or a list of paths and reports the paths that became ignored
+// and those that became included. Like diff(1), it exits with 1 if a path
+// changed, 0 otherwise.
+func diff(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("diff", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "usage: pathignore diff [flags] <old> <new> [dir]")
+		fmt.Fprintln(stderr, "   or: pathignore diff [flags] --stdin <old> <new>")
+		fmt.Fprintln(stderr, "\n<old> and <new> are YAML, JSON or TOML config files, or gitignore files.")
+		fs.PrintDefaults()
+	}
+
+	var (
+		readStdin  bool
+		nul        bool
+		ignoreCase bool
+		format     string
+	)
+	fs.BoolVar(&readStdin, "stdin", false, "evaluate the paths read from stdin, one per line, instead of a directory")
+	fs.BoolVar(&nul, "z", false, "paths are NUL separated on stdin")
+	fs.BoolVar(&ignoreCase, "ignore-case", false, "match case-insensitively, like core.ignoreCase")
+	fs.StringVar(&format, "format", "text", "output `format`: text or json")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+		return exitFatal
+	}
+
+	dir := "."
+	switch {
+	case fs.NArg() < 2:
+		return fatal(stderr, errors.New("old and new rules required"))
+	case readStdin && fs.NArg() > 2:
+		return fatal(stderr, errors.New("cannot specify a directory with --stdin"))
+	case fs.NArg() == 3:
+		dir = fs.Arg(2)
+	case fs.NArg() > 3:
+		return fatal(stderr, errors.New("too many directories"))
+	}
+	switch {
+	case nul && !readStdin:
+		return fatal(stderr, errors.New("-z only makes sense with --stdin"))
+	case format != "text" && format != "json":
+		return fatal(stderr, fmt.Errorf("unknown format %q", format))
+	}
+
+	oldOpts, err := loadRules(fs.Arg(0), ignoreCase)
+	if err != nil {
+		return fatal(stderr, err)
+	}
+	newOpts, err := loadRules(fs.Arg(1), ignoreCase)
+	if err != nil {
+		return fatal(stderr, err)
+	}
+	d, err := gopathignore.NewDiff(oldOpts, newOpts)
+	if err != nil {
+		return fatal(stderr, err)
+	}
+
+	// Like ls, the tree is walked from the current directory, so that paths
+	// are the same as when read from stdin.
+	ctx := context.Background()
+	root := treeRoot(dir)
+	if readStdin {
+		err = scanPaths(stdin, nul, root, func(path string) error {
+			_, _, err := d.Add(ctx, path)
+			return err
+		})
+	} else if info, statErr := os.Stat(dir); statErr != nil {
+		err = statErr
+	} else if !info.IsDir() {
+		err = fmt.Errorf("%s: not a directory", dir)
+	} else {
+		var start string
+		if start, err = relPath(root, dir); err == nil {
+			err = d.AddFS(ctx, os.DirFS(root), strings.TrimSuffix(start, "/"), warn(stderr))
+		}
+	}
+	if err != nil {
+		return fatal(stderr, err)
+	}
+
+	r := d.Report()
+	if format == "json" {
+		err = writeDiffJSON(stdout, root, r)
+	} else {
+		err = writeDiffText(stdout, root, r)
+	}
+	if err != nil {
+		return fatal(stderr, err)
+	}
+	if len(r.Ignored) > 0 || len(r.Included) > 0 {
+		return exitNone
+	}
+	return exitOK
+}
+
+// loadRules returns the options of a config file or, for files without a config
+// extension, of a gitignore file.
+func loadRules(path string, ignoreCase bool) (gopathignore.Options, error) {
+	var opts gopathignore.Options
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml", ".json", ".toml":
+		var err error
+		if opts, err = gopathignore.LoadConfig(path); err != nil {
+			return opts, err
+		}
+	default:
+		if _, err := os.Stat(path); err != nil {
+			return opts, err
+		}
+		opts.GitIgnore = &gitignore.Options{FilePath: path}
+	}
+	opts.IgnoreCase = opts.IgnoreCase || ignoreCase
+	return opts, nil
+}
+
+// sideRule returns the rule deciding one side of a change, given the result of
+// that side: the rule that ignored the path or the negation that included it. ok
+// is false if no rule matched.
+func sideRule(info match.MatchInfo, c gopathignore.Change) (typ match.Type, src match.Source, ok bool) {
+	switch {
+	case info.Ok():
+		return info.Type(), info.Source(), true
+	case c.Negation != nil:
+		return c.Negation.Type, c.Negation.Source, true
+	}
+	return 0, match.Source{}, false
+}
+
+// matchSource returns the source:line:pattern of the rule deciding a side as
+// check-ignore -v prints it, :: if no rule matched.
+func matchSource(info match.MatchInfo, c gopathignore.Change) string {
+	typ, src, ok := sideRule(info, c)
+	if !ok {
+		return "::"
+	}
+	return ruleLocation(typ, src) + ":" + src.Pattern
+}
+
+// writeDiffText prints a line per changed path, + for paths that became ignored
+// and - for those that became included, followed by the old and new matches.
+func writeDiffText(w io.Writer, root string, r gopathignore.DiffReport) error {
+	display := displayPaths(root)
+	out := bufio.NewWriter(w)
+	for _, c := range r.Ignored {
+		fmt.Fprintf(out, "+ %s\t%s\t%s\n", display(c.Path), matchSource(c.Old, c), matchSource(c.New, c))
+	}
+	for _, c := range r.Included {
+		fmt.Fprintf(out, "- %s\t%s\t%s\n", display(c.Path), matchSource(c.Old, c), matchSource(c.New, c))
+	}
+	return out.Flush()
+}
+
+// diffChange is a changed path in the json format, with the rule deciding the
+// old and the new side, see sideRule.
+type diffChange struct {
+	Path string   `json:"path"`
+	Old  *lsMatch `json:"old"`
+	New  *lsMatch `json:"new"`
+}
+
+func writeDiffJSON(w io.Writer, root string, r gopathignore.DiffReport) error {
+	toMatch := func(info match.MatchInfo, c gopathignore.Change) *lsMatch {
+		typ, src, ok := sideRule(info, c)
+		if !ok {
+			return nil
+		}
+		return &lsMatch{
+			Type:    typ.String(),
+			Pattern: src.Pattern,
+			File:    src.File,
+			Line:    src.Line,
+			Negate:  src.Negate,
+		}
+	}
+	display := displayPaths(root)
+	changes := func(cs []gopathignore.Change) []diffChange {
+		res := make([]diffChange, 0, len(cs))
+		for _, c := range cs {
+			res = append(res, diffChange{Path: display(c.Path), Old: toMatch(c.Old, c), New: toMatch(c.New, c)})
+		}
+		return res
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(struct {
+		Paths    int          `json:"paths"`
+		Ignored  []diffChange `json:"ignored"`
+		Included []diffChange `json:"included"`
+	}{r.Paths, changes(r.Ignored), changes(r.Included)})
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiff(t *testing.T) {
+	newTree(t, map[string]string{
+		"old.gitignore": "*.log\nbuild/\n",
+		"new.gitignore": "*.log\n!keep.log\n",
+		"new.yaml":      "gitignore:\n  patterns: ['*.log', 'build/']\nglob:\n  patterns: ['*.go']\n",
+		".git/a.log":    "",
+		"a.log":         "",
+		"keep.log":      "",
+		"build/x":       "",
+		"main.go":       "",
+	})
+
+	code, out, _ := runCmd("", "diff", "old.gitignore", "new.gitignore")
+	require.Equal(t, exitNone, code)
+	require.Equal(t, "- build/\told.gitignore:2:build/\t::\n"+
+		"- build/x\told.gitignore:2:build/\t::\n"+
+		"- keep.log\told.gitignore:1:*.log\tnew.gitignore:2:!keep.log\n", out)
+
+	// Paths below a listed subdirectory stay relative to the current directory.
+	code, out, _ = runCmd("", "diff", "old.gitignore", "new.gitignore", "build")
+	require.Equal(t, exitNone, code)
+	require.Equal(t, "- build/\told.gitignore:2:build/\t::\n"+
+		"- build/x\told.gitignore:2:build/\t::\n", out)
+
+	code, out, _ = runCmd("", "diff", "old.gitignore", "new.yaml")
+	require.Equal(t, exitNone, code)
+	require.Equal(t, "+ main.go\t::\tglob:1:*.go\n", out)
+
+	code, out, _ = runCmd("", "diff", "old.gitignore", "old.gitignore")
+	require.Equal(t, exitOK, code)
+	require.Empty(t, out)
+
+	code, out, _ = runCmd("keep.log\x00build\x00main.go\x00", "diff", "--stdin", "-z", "--format", "json", "new.gitignore", "new.yaml")
+	require.Equal(t, exitNone, code)
+	var report struct {
+		Paths    int
+		Ignored  []diffChange
+		Included []diffChange
+	}
+	require.NoError(t, json.Unmarshal([]byte(out), &report))
+	require.Equal(t, 3, report.Paths)
+	require.Equal(t, []diffChange{
+		{
+			Path: "keep.log",
+			Old:  &lsMatch{Type: "gitignore", Pattern: "!keep.log", File: "new.gitignore", Line: 2, Negate: true},
+			New:  &lsMatch{Type: "gitignore", Pattern: "*.log", Line: 1},
+		},
+		{Path: "build/", New: &lsMatch{Type: "gitignore", Pattern: "build/", Line: 2}},
+		{Path: "main.go", New: &lsMatch{Type: "glob", Pattern: "*.go", Line: 1}},
+	}, report.Ignored)
+	require.Empty(t, report.Included)
+
+	code, out, _ = runCmd("keep.log\n", "diff", "--stdin", "--format", "json", "old.gitignore", "new.gitignore")
+	require.Equal(t, exitNone, code)
+	require.NoError(t, json.Unmarshal([]byte(out), &report))
+	require.Equal(t, []diffChange{{
+		Path: "keep.log",
+		Old:  &lsMatch{Type: "gitignore", Pattern: "*.log", File: "old.gitignore", Line: 1},
+		New:  &lsMatch{Type: "gitignore", Pattern: "!keep.log", File: "new.gitignore", Line: 2, Negate: true},
+	}}, report.Included)
+
+	for _, args := range [][]string{
+		{"diff", "old.gitignore"},
+		{"diff", "old.gitignore", "new.gitignore", "a", "b"},
+		{"diff", "--stdin", "old.gitignore", "new.gitignore", "."},
+		{"diff", "-z", "old.gitignore", "new.gitignore"},
+		{"diff", "--format", "xml", "old.gitignore", "new.gitignore"},
+		{"diff", "missing", "new.gitignore"},
+		{"diff", "old.gitignore", "new.gitignore", "a.log"},
+	} {
+		code, _, _ := runCmd("", args...)
+		require.Equal(t, exitFatal, code, args)
+	}
+}
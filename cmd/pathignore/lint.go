@@ -0,0 +1,93 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/vbhat161/go-path-ignore/lint"
+)
+
+// lintRules implements the lint command, which reports problems with the rules
+// selected by the rule flags. It exits with 1 if a warning or an error is
+// reported, 0 otherwise.
+func lintRules(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("lint", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "usage: pathignore lint [flags]")
+		fs.PrintDefaults()
+	}
+
+	var (
+		rules    ruleFlags
+		severity lint.Severity
+		format   string
+	)
+	rules.register(fs)
+	fs.TextVar(&severity, "severity", lint.Info, "report findings of at least `level`: info, warning or error")
+	fs.StringVar(&format, "format", "text", "output `format`: text or json")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+		return exitFatal
+	}
+	switch {
+	case fs.NArg() > 0:
+		return fatal(stderr, errors.New("lint takes no arguments"))
+	case format != "text" && format != "json":
+		return fatal(stderr, fmt.Errorf("unknown format %q", format))
+	}
+
+	opts, _, err := rules.options(treeRoot("."))
+	if err != nil {
+		return fatal(stderr, err)
+	}
+	findings, err := lint.Lint(opts)
+	if err != nil {
+		return fatal(stderr, err)
+	}
+
+	type jsonFinding struct {
+		Severity lint.Severity `json:"severity"`
+		Check    lint.Check    `json:"check"`
+		Type     string        `json:"type"`
+		File     string        `json:"file,omitempty"`
+		Line     int           `json:"line"`
+		Pattern  string        `json:"pattern"`
+		Message  string        `json:"message"`
+	}
+	code, reported := exitOK, []jsonFinding{}
+	for _, f := range findings {
+		if f.Severity < severity {
+			continue
+		}
+		if f.Severity >= lint.Warning {
+			code = exitNone
+		}
+		if format == "text" {
+			fmt.Fprintln(stdout, f)
+			continue
+		}
+		reported = append(reported, jsonFinding{
+			Severity: f.Severity,
+			Check:    f.Check,
+			Type:     f.Type.String(),
+			File:     f.Source.File,
+			Line:     f.Source.Line,
+			Pattern:  f.Source.Pattern,
+			Message:  f.Message,
+		})
+	}
+	if format == "json" {
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(reported); err != nil {
+			return fatal(stderr, err)
+		}
+	}
+	return code
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLint(t *testing.T) {
+	newTree(t, map[string]string{
+		".gitignore": "build/\n!build/keep\n*.tmp  \n",
+		"clean.yaml": "glob:\n  patterns: ['*.go']\n",
+	})
+
+	code, out, _ := runCmd("", "lint", "--exclude-from", ".gitignore")
+	require.Equal(t, exitNone, code)
+	require.Equal(t, ".gitignore:2: error: cannot re-include a path inside a directory excluded by .gitignore:1 (negation)\n"+
+		".gitignore:3: info: trailing spaces are ignored, escape them with a backslash to match them (git-compat)\n", out)
+
+	code, out, _ = runCmd("", "lint", "--exclude", "*.tmp  ")
+	require.Equal(t, exitOK, code)
+	require.Contains(t, out, "gitignore:1: info: ")
+
+	code, out, _ = runCmd("", "lint", "--severity", "warning", "--format", "json", "--glob", "*.go", "--glob", "main.go")
+	require.Equal(t, exitNone, code)
+	var findings []map[string]any
+	require.NoError(t, json.Unmarshal([]byte(out), &findings))
+	require.Equal(t, []map[string]any{{
+		"severity": "warning",
+		"check":    "shadowed",
+		"type":     "glob",
+		"line":     float64(2),
+		"pattern":  "main.go",
+		"message":  "redundant, glob:1 matches every path it does",
+	}}, findings)
+
+	code, out, _ = runCmd("", "lint", "--config", "clean.yaml", "--format", "json")
+	require.Equal(t, exitOK, code)
+	require.Equal(t, "[]\n", out)
+
+	for _, args := range [][]string{
+		{"lint", "extra"},
+		{"lint", "--severity", "fatal"},
+		{"lint", "--format", "xml"},
+		{"lint", "--exclude-from", "missing"},
+	} {
+		code, _, _ := runCmd("", args...)
+		require.Equal(t, exitFatal, code, args)
+	}
+}
@@ -0,0 +1,280 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	pathpkg "path"
+	"path/filepath"
+	"strings"
+
+	gopathignore "github.com/vbhat161/go-path-ignore"
+	"github.com/vbhat161/go-path-ignore/match"
+)
+
+// ls implements the ls command, which lists the files under a directory that are
+// not ignored, like git ls-files --others --exclude-standard does for untracked
+// files.
+func ls(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("ls", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "usage: pathignore ls [flags] [dir]")
+		fs.PrintDefaults()
+	}
+
+	var (
+		rules   ruleFlags
+		ignored bool
+		dirs    bool
+		nul     bool
+		format  string
+	)
+	rules.register(fs)
+	fs.BoolVar(&ignored, "ignored", false, "list the ignored files instead")
+	fs.BoolVar(&dirs, "dirs", false, "list ignored directories as dir/ instead of pruning them silently or listing their files")
+	fs.BoolVar(&nul, "0", false, "terminate text output lines with NUL instead of newline")
+	fs.StringVar(&format, "format", "text", "output `format`: text, json or ndjson")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+		return exitFatal
+	}
+
+	dir := "."
+	switch fs.NArg() {
+	case 0:
+	case 1:
+		dir = fs.Arg(0)
+	default:
+		return fatal(stderr, errors.New("too many directories"))
+	}
+	switch format {
+	case "text", "json", "ndjson":
+	default:
+		return fatal(stderr, fmt.Errorf("unknown format %q", format))
+	}
+	if nul && format != "text" {
+		return fatal(stderr, errors.New("-0 only makes sense with the text format"))
+	}
+
+	defaultRoot := treeRoot(dir)
+	opts, root, err := rules.options(defaultRoot)
+	if err != nil {
+		return fatal(stderr, err)
+	}
+	if fs.NArg() == 0 && root != defaultRoot {
+		dir = root
+	}
+	start, err := relPath(root, dir)
+	if err != nil {
+		return fatal(stderr, err)
+	}
+	pi, err := gopathignore.New(opts)
+	if err != nil {
+		return fatal(stderr, err)
+	}
+
+	out := bufio.NewWriter(stdout)
+	l := &lister{pi: pi, display: displayPaths(root), out: out, onErr: warn(stderr), ignored: ignored, dirs: dirs, nul: nul, format: format}
+	if err := l.walk(context.Background(), os.DirFS(root), strings.TrimSuffix(start, "/")); err != nil {
+		return fatal(stderr, err)
+	}
+	if err := l.flush(); err != nil {
+		return fatal(stderr, err)
+	}
+	return exitOK
+}
+
+// lsEntry is a listed path in the json and ndjson formats.
+type lsEntry struct {
+	Path    string   `json:"path"`
+	Dir     bool     `json:"dir,omitempty"`
+	Ignored bool     `json:"ignored"`
+	Match   *lsMatch `json:"match,omitempty"`
+}
+
+// lsMatch is the rule that ignored an entry.
+type lsMatch struct {
+	Type    string `json:"type"`
+	Pattern string `json:"pattern"`
+	File    string `json:"file,omitempty"`
+	Line    int    `json:"line"`
+	Negate  bool   `json:"negate,omitempty"`
+}
+
+type lister struct {
+	pi *gopathignore.PathIgnore
+	// display turns the walked paths, relative to the root of the rules, into
+	// the paths printed, see displayPaths.
+	display func(path string) string
+	out     *bufio.Writer
+	onErr   gopathignore.ErrFunc
+	entries []lsEntry
+
+	ignored, dirs, nul bool
+	format             string
+}
+
+// walk lists the entries below start. Ignored directories are pruned unless the
+// ignored files are listed, in which case their files are listed with the match
+// of the directory, as git does not look into excluded directories.
+func (l *lister) walk(ctx context.Context, fsys fs.FS, start string) error {
+	var (
+		prefix string
+		pruned match.MatchInfo
+	)
+	return fs.WalkDir(fsys, start, gopathignore.SkipGitDirs(func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if d == nil || path == start {
+				return err
+			}
+			return l.onErr(path, err)
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if path == "." {
+			return nil
+		}
+
+		var info match.MatchInfo
+		if prefix != "" && strings.HasPrefix(path, prefix) {
+			info = pruned
+		} else {
+			prefix = ""
+			if info, err = l.pi.MatchEntry(ctx, path, d.IsDir()); err != nil {
+				return err
+			}
+		}
+
+		if !info.Ok() {
+			if !d.IsDir() && !l.ignored {
+				return l.emit(path, false, nil)
+			}
+			return nil
+		}
+		if !d.IsDir() {
+			if l.ignored {
+				return l.emit(path, false, info)
+			}
+			return nil
+		}
+		if l.dirs {
+			if err := l.emit(path, true, info); err != nil {
+				return err
+			}
+			return fs.SkipDir
+		}
+		if !l.ignored {
+			return fs.SkipDir
+		}
+		if prefix == "" {
+			prefix, pruned = path+"/", info
+		}
+		return nil
+	}))
+}
+
+func (l *lister) emit(path string, dir bool, info match.MatchInfo) error {
+	path = l.display(path)
+	if dir {
+		path += "/"
+	}
+	if l.format == "text" {
+		l.out.WriteString(path)
+		if l.nul {
+			return l.out.WriteByte(0)
+		}
+		return l.out.WriteByte('\n')
+	}
+
+	e := lsEntry{Path: path, Dir: dir, Ignored: info != nil}
+	if info != nil {
+		src := info.Source()
+		e.Match = &lsMatch{
+			Type:    info.Type().String(),
+			Pattern: src.Pattern,
+			File:    src.File,
+			Line:    src.Line,
+			Negate:  src.Negate,
+		}
+	}
+	if l.format == "json" {
+		l.entries = append(l.entries, e)
+		return nil
+	}
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	l.out.Write(data)
+	return l.out.WriteByte('\n')
+}
+
+// flush writes the json array, if any, and flushes the output.
+func (l *lister) flush() error {
+	if l.format == "json" {
+		entries := l.entries
+		if entries == nil {
+			entries = []lsEntry{}
+		}
+		enc := json.NewEncoder(l.out)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(entries); err != nil {
+			return err
+		}
+	}
+	return l.out.Flush()
+}
+
+// displayPaths returns a function turning slash separated paths relative to root
+// into the paths the commands print: relative to the current directory, or
+// joined to root if it is absolute.
+func displayPaths(root string) func(path string) string {
+	if root == "." {
+		return func(path string) string { return path }
+	}
+	join := func(path string) string {
+		joined := pathpkg.Join(filepath.ToSlash(root), path)
+		if strings.HasSuffix(path, "/") {
+			joined += "/"
+		}
+		return joined
+	}
+	wd, err := os.Getwd()
+	if filepath.IsAbs(root) || err != nil {
+		return join
+	}
+
+	// The root may be a parent of the current directory, which joining it
+	// does not resolve.
+	absRoot := filepath.Join(wd, root)
+	return func(path string) string {
+		rel, err := filepath.Rel(wd, filepath.Join(absRoot, filepath.FromSlash(path)))
+		if err != nil {
+			return join(path)
+		}
+		rel = filepath.ToSlash(rel)
+		if strings.HasSuffix(path, "/") {
+			rel += "/"
+		}
+		return rel
+	}
+}
+
+// warn returns an ErrFunc that prints the errors of the entries a walk cannot
+// read as warnings and skips them.
+func warn(stderr io.Writer) gopathignore.ErrFunc {
+	return func(path string, err error) error {
+		fmt.Fprintf(stderr, "warning: %v\n", err)
+		return nil
+	}
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLs(t *testing.T) {
+	newTree(t, map[string]string{
+		".gitignore":       "*.log\n!keep.log\nbuild/\n",
+		".git/HEAD":        "",
+		"a.log":            "",
+		"keep.log":         "",
+		"main.go":          "",
+		"build/out.bin":    "",
+		"build/sub/x.bin":  "",
+		"src/.gitignore":   "gen.go\n",
+		"src/gen.go":       "",
+		"src/lib.go":       "",
+		"src/empty/":       "",
+		"src/build/y.go":   "",
+		"src/keep/app.log": "",
+	})
+
+	code, out, _ := runCmd("", "ls")
+	require.Equal(t, exitOK, code)
+	require.Equal(t, ".gitignore\nkeep.log\nmain.go\nsrc/.gitignore\nsrc/lib.go\n", out)
+
+	code, out, _ = runCmd("", "ls", "--ignored")
+	require.Equal(t, exitOK, code)
+	require.Equal(t, "a.log\nbuild/out.bin\nbuild/sub/x.bin\nsrc/build/y.go\nsrc/gen.go\nsrc/keep/app.log\n", out)
+
+	code, out, _ = runCmd("", "ls", "--ignored", "--dirs", "-0")
+	require.Equal(t, exitOK, code)
+	require.Equal(t, "a.log\x00build/\x00src/build/\x00src/gen.go\x00src/keep/app.log\x00", out)
+
+	code, out, _ = runCmd("", "ls", "--exclude", "*.go", "src")
+	require.Equal(t, exitOK, code)
+	require.Equal(t, "src/.gitignore\nsrc/keep/app.log\n", out)
+
+	// The rules of the parent directories apply to a listed subdirectory, and
+	// paths stay relative to the current directory.
+	for _, args := range [][]string{{"ls", "src"}, {"ls", "--root", ".", "src"}, {"ls", "src/build/.."}} {
+		code, out, _ = runCmd("", args...)
+		require.Equal(t, exitOK, code, args)
+		require.Equal(t, "src/.gitignore\nsrc/lib.go\n", out, args)
+	}
+
+	code, out, _ = runCmd("", "ls", "--root", "src")
+	require.Equal(t, exitOK, code)
+	require.Equal(t, "src/.gitignore\nsrc/build/y.go\nsrc/keep/app.log\nsrc/lib.go\n", out)
+
+	// From a subdirectory, the rules are rooted at the top of the work tree and
+	// the current directory is listed.
+	t.Chdir("src")
+	code, out, _ = runCmd("", "ls", "--ignored")
+	require.Equal(t, exitOK, code)
+	require.Equal(t, "build/y.go\ngen.go\nkeep/app.log\n", out)
+
+	code, out, _ = runCmd("", "ls", "../build")
+	require.Equal(t, exitOK, code)
+	require.Empty(t, out)
+
+	code, out, _ = runCmd("", "ls", "--ignored", "--dirs", "..")
+	require.Equal(t, exitOK, code)
+	require.Equal(t, "../a.log\n../build/\nbuild/\ngen.go\nkeep/app.log\n", out)
+}
+
+func TestLsJSON(t *testing.T) {
+	newTree(t, map[string]string{
+		".gitignore": "*.log\nbuild/\n",
+		"a.log":      "",
+		"build/x":    "",
+		"main.go":    "",
+	})
+
+	code, out, _ := runCmd("", "ls", "--ignored", "--format", "json")
+	require.Equal(t, exitOK, code)
+	var entries []lsEntry
+	require.NoError(t, json.Unmarshal([]byte(out), &entries))
+	require.Equal(t, []lsEntry{
+		{Path: "a.log", Ignored: true, Match: &lsMatch{Type: "gitignore", Pattern: "*.log", File: ".gitignore", Line: 1}},
+		{Path: "build/x", Ignored: true, Match: &lsMatch{Type: "gitignore", Pattern: "build/", File: ".gitignore", Line: 2}},
+	}, entries)
+
+	code, out, _ = runCmd("", "ls", "--dirs", "--format", "ndjson")
+	require.Equal(t, exitOK, code)
+	lines := strings.Split(strings.TrimSuffix(out, "\n"), "\n")
+	require.Equal(t, []string{
+		`{"path":".gitignore","ignored":false}`,
+		`{"path":"build/","dir":true,"ignored":true,"match":{"type":"gitignore","pattern":"build/","file":".gitignore","line":2}}`,
+		`{"path":"main.go","ignored":false}`,
+	}, lines)
+
+	code, out, _ = runCmd("", "ls", "--format", "json", "--glob", "*")
+	require.Equal(t, exitOK, code)
+	require.Equal(t, "[]\n", out)
+}
+
+func TestLsUsageErrors(t *testing.T) {
+	newTree(t, nil)
+
+	for _, args := range [][]string{
+		{"ls", "a", "b"},
+		{"ls", "--format", "xml"},
+		{"ls", "-0", "--format", "json"},
+		{"ls", "missing"},
+		{"ls", "--root", ".", ".."},
+	} {
+		code, _, _ := runCmd("", args...)
+		require.Equal(t, exitFatal, code, args)
+	}
+}
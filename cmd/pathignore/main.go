@@ -0,0 +1,80 @@
+// Command pathignore matches paths against ignore rules from the command line.
+//
+// Usage:
+//
+//	pathignore <command> [flags] [args]
+//
+// The commands are:
+//
+//	check-ignore  report which paths are ignored, like git check-ignore
+//	ls            list the files under a directory that are not ignored
+//	lint          report duplicate, shadowed and ineffective rules
+//	coverage      report how often each rule matches a directory or paths
+//	diff          report the paths whose status differs between two rule sets
+//
+// Run "pathignore <command> -h" for the flags of a command.
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// Exit codes shared by the commands, those of git check-ignore.
+const (
+	exitOK    = 0
+	exitNone  = 1
+	exitFatal = 128
+)
+
+type command struct {
+	name  string
+	short string
+	run   func(args []string, stdin io.Reader, stdout, stderr io.Writer) int
+}
+
+var commands = []command{
+	{name: "check-ignore", short: "report which paths are ignored, like git check-ignore", run: checkIgnore},
+	{name: "ls", short: "list the files under a directory that are not ignored", run: ls},
+	{name: "lint", short: "report duplicate, shadowed and ineffective rules", run: lintRules},
+	{name: "coverage", short: "report how often each rule matches a directory or paths", run: coverage},
+	{name: "diff", short: "report the paths whose status differs between two rule sets", run: diff},
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) == 0 || args[0] == "-h" || args[0] == "--help" || args[0] == "help" {
+		usage(stderr)
+		if len(args) == 0 {
+			return exitFatal
+		}
+		return exitOK
+	}
+
+	for _, cmd := range commands {
+		if cmd.name == args[0] {
+			return cmd.run(args[1:], stdin, stdout, stderr)
+		}
+	}
+	fmt.Fprintf(stderr, "pathignore: unknown command %q\n", args[0])
+	usage(stderr)
+	return exitFatal
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "usage: pathignore <command> [flags] [args]")
+	fmt.Fprintln(w, "\ncommands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %-14s %s\n", cmd.name, cmd.short)
+	}
+}
+
+// fatal reports err the way git does and returns exitFatal.
+func fatal(stderr io.Writer, err error) int {
+	fmt.Fprintf(stderr, "fatal: %v\n", err)
+	return exitFatal
+}
@@ -0,0 +1,182 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	gopathignore "github.com/vbhat161/go-path-ignore"
+	"github.com/vbhat161/go-path-ignore/match/gitignore"
+	"github.com/vbhat161/go-path-ignore/match/glob"
+	"github.com/vbhat161/go-path-ignore/match/regex"
+)
+
+// ruleFlags are the flags selecting the rules of a command, either a config file
+// or patterns and gitignore sources. Without any, the .gitignore files under the
+// default root, usually the top of the git work tree, and git's standard exclude
+// sources apply, as for git.
+type ruleFlags struct {
+	config          string
+	root            string
+	file            string
+	patterns        listFlag
+	globs           listFlag
+	regexes         listFlag
+	excludeStandard bool
+	ignoreCase      bool
+	parallel        bool
+}
+
+// listFlag is a flag that can be repeated.
+type listFlag []string
+
+func (l *listFlag) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *listFlag) Set(v string) error {
+	*l = append(*l, v)
+	return nil
+}
+
+func (f *ruleFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&f.config, "config", "", "read the rules from a YAML, JSON or TOML config `file`")
+	fs.StringVar(&f.root, "root", "", "load the .gitignore files under `dir`, paths are relative to it")
+	fs.StringVar(&f.file, "exclude-from", "", "read gitignore patterns from `file`")
+	fs.Var(&f.patterns, "exclude", "add a gitignore `pattern`, can be repeated")
+	fs.Var(&f.globs, "glob", "add a glob `pattern`, can be repeated")
+	fs.Var(&f.regexes, "regex", "add an RE2 `pattern`, can be repeated")
+	fs.BoolVar(&f.excludeStandard, "exclude-standard", false, "also apply core.excludesFile and $GIT_DIR/info/exclude of the root")
+	fs.BoolVar(&f.ignoreCase, "ignore-case", false, "match case-insensitively, like core.ignoreCase")
+	fs.BoolVar(&f.parallel, "parallel", false, "match the rules of each strategy with a single RE2 set")
+}
+
+// options returns the options the flags describe and the directory paths are
+// relative to. Unless the flags name a root, it is defaultRoot.
+func (f *ruleFlags) options(defaultRoot string) (gopathignore.Options, string, error) {
+	sources := f.root != "" || f.file != "" || len(f.patterns) > 0 || len(f.globs) > 0 || len(f.regexes) > 0 || f.excludeStandard
+	if f.config != "" {
+		if sources {
+			return gopathignore.Options{}, "", fmt.Errorf("--config cannot be combined with pattern flags")
+		}
+		opts, err := gopathignore.LoadConfig(f.config)
+		if err != nil {
+			return gopathignore.Options{}, "", err
+		}
+		opts.IgnoreCase = opts.IgnoreCase || f.ignoreCase
+		opts.Parallel = opts.Parallel || f.parallel
+		root := defaultRoot
+		if opts.GitIgnore != nil && opts.GitIgnore.Root != "" {
+			root = opts.GitIgnore.Root
+		}
+		return opts, root, nil
+	}
+
+	opts := gopathignore.Options{IgnoreCase: f.ignoreCase, Parallel: f.parallel}
+	root := f.root
+	if !sources {
+		root, f.excludeStandard = defaultRoot, true
+	}
+	if root == "" && f.excludeStandard {
+		root = defaultRoot
+	}
+	if root != "" || f.file != "" || len(f.patterns) > 0 {
+		opts.GitIgnore = &gitignore.Options{
+			Patterns:        f.patterns,
+			FilePath:        f.file,
+			Root:            root,
+			ExcludeStandard: f.excludeStandard,
+		}
+	}
+	if len(f.globs) > 0 {
+		opts.Glob = &glob.Options{Patterns: f.globs}
+	}
+	if len(f.regexes) > 0 {
+		opts.Regex = &regex.Options{Patterns: f.regexes}
+	}
+	if root == "" {
+		root = defaultRoot
+	}
+	return opts, root, nil
+}
+
+// treeRoot returns the default root of the rules for walking dir: the top of the
+// git work tree holding dir, or outside of one the current directory, or dir
+// itself if it lies outside of it.
+func treeRoot(dir string) string {
+	if top, ok := workTree(dir); ok {
+		return top
+	}
+	if _, err := relPath(".", dir); err != nil {
+		return dir
+	}
+	return "."
+}
+
+// workTree finds the top of the git work tree holding dir like git does, as the
+// nearest of dir and its parents with a .git entry. It is relative to the
+// current directory, unless dir is absolute and the current directory lies
+// outside of the work tree.
+func workTree(dir string) (string, bool) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return "", false
+	}
+	top := abs
+	for {
+		if _, err := os.Lstat(filepath.Join(top, ".git")); err == nil {
+			break
+		}
+		parent := filepath.Dir(top)
+		if parent == top {
+			return "", false
+		}
+		top = parent
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		return top, true
+	}
+	rel, err := filepath.Rel(wd, top)
+	if err != nil {
+		return top, true
+	}
+	if filepath.IsAbs(dir) {
+		if _, err := relPath(top, "."); err != nil {
+			return top, true
+		}
+	}
+	return rel, true
+}
+
+// relPath returns the slash separated path of a command line path relative to
+// root, keeping a trailing slash that marks a directory.
+func relPath(root, path string) (string, error) {
+	dir := strings.HasSuffix(path, "/") || strings.HasSuffix(path, string(filepath.Separator))
+	rel := filepath.Clean(path)
+	if filepath.IsAbs(rel) || root != "." {
+		absRoot, err := filepath.Abs(root)
+		if err != nil {
+			return "", err
+		}
+		absPath, err := filepath.Abs(rel)
+		if err != nil {
+			return "", err
+		}
+		if rel, err = filepath.Rel(absRoot, absPath); err != nil {
+			return "", err
+		}
+	}
+	if rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("%s: '%s' is outside the root", path, filepath.ToSlash(rel))
+	}
+
+	rel = filepath.ToSlash(rel)
+	if dir && rel != "." {
+		rel += "/"
+	}
+	return rel, nil
+}
@@ -0,0 +1,258 @@
+package gopathignore
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	gobwas "github.com/gobwas/glob"
+	"github.com/vbhat161/go-path-ignore/match/gitignore"
+	"github.com/vbhat161/go-path-ignore/match/glob"
+	"github.com/vbhat161/go-path-ignore/match/regex"
+	regexp "github.com/wasilibs/go-re2"
+)
+
+// Config is the declarative form of Options, read from a YAML, JSON or TOML file
+// by LoadConfig. Keys are snake_case, see ConfigSchema for the full schema.
+type Config struct {
+	Regex      *RegexConfig     `json:"regex,omitempty"`
+	Glob       *GlobConfig      `json:"glob,omitempty"`
+	GitIgnore  *GitIgnoreConfig `json:"gitignore,omitempty"`
+	Timeout    Duration         `json:"timeout,omitempty"`
+	Parallel   bool             `json:"parallel,omitempty"`
+	SinglePass bool             `json:"single_pass,omitempty"`
+	IgnoreCase bool             `json:"ignore_case,omitempty"`
+	Workers    int              `json:"workers,omitempty"`
+	CacheSize  int              `json:"cache_size,omitempty"`
+}
+
+type RegexConfig struct {
+	Patterns   []string `json:"patterns,omitempty"`
+	Literals   bool     `json:"literals,omitempty"`
+	IgnoreCase bool     `json:"ignore_case,omitempty"`
+}
+
+type GlobConfig struct {
+	Patterns    []string `json:"patterns,omitempty"`
+	RawPatterns []string `json:"raw_patterns,omitempty"`
+	IgnoreCase  bool     `json:"ignore_case,omitempty"`
+}
+
+type GitIgnoreConfig struct {
+	Patterns []string `json:"patterns,omitempty"`
+	// File and Root are relative to the directory of the config file.
+	File            string `json:"file,omitempty"`
+	Root            string `json:"root,omitempty"`
+	ExcludeStandard bool   `json:"exclude_standard,omitempty"`
+	// Engine is "regex" (the default) or "wildmatch".
+	Engine     string `json:"engine,omitempty"`
+	IgnoreCase bool   `json:"ignore_case,omitempty"`
+}
+
+// Duration is a time.Duration written as a string such as "500ms" or "2s".
+type Duration time.Duration
+
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(time.Duration(d).String()), nil
+}
+
+func (d *Duration) UnmarshalText(text []byte) error {
+	v, err := time.ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+	*d = Duration(v)
+	return nil
+}
+
+// ConfigError is a problem found in a config file. Line and Column are 1-based,
+// zero if the position is unknown.
+type ConfigError struct {
+	File   string
+	Line   int
+	Column int
+	// Key is the dotted path of the offending value, e.g. "regex.patterns[2]",
+	// empty for syntax errors.
+	Key string
+	Err error
+}
+
+func (e *ConfigError) Error() string {
+	var sb strings.Builder
+	sb.WriteString(e.File)
+	if e.Line > 0 {
+		fmt.Fprintf(&sb, ":%d", e.Line)
+		if e.Column > 0 {
+			fmt.Fprintf(&sb, ":%d", e.Column)
+		}
+	}
+	sb.WriteString(": ")
+	if e.Key != "" {
+		sb.WriteString(e.Key + ": ")
+	}
+	sb.WriteString(e.Err.Error())
+	return sb.String()
+}
+
+func (e *ConfigError) Unwrap() error {
+	return e.Err
+}
+
+// LoadConfig reads the config file at path and returns the Options it describes.
+// The format follows the file extension: .yaml or .yml, .json or .toml. Invalid
+// files are reported with a *ConfigError per problem, joined with errors.Join.
+func LoadConfig(path string) (Options, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Options{}, err
+	}
+	cfg, err := ParseConfig(path, data)
+	if err != nil {
+		return Options{}, err
+	}
+	return cfg.Options(filepath.Dir(path)), nil
+}
+
+// ParseConfig parses and validates a config file named name, whose extension
+// selects the format as for LoadConfig.
+func ParseConfig(name string, data []byte) (*Config, error) {
+	var root *cfgNode
+	var err error
+	switch ext := strings.ToLower(filepath.Ext(name)); ext {
+	case ".yaml", ".yml":
+		root, err = parseYAML(data)
+	case ".json":
+		root, err = parseJSON(data)
+	case ".toml":
+		root, err = parseTOML(data)
+	default:
+		return nil, fmt.Errorf("unknown config format %q", ext)
+	}
+	if err != nil {
+		var cerr *ConfigError
+		if errors.As(err, &cerr) {
+			cerr.File = name
+		}
+		return nil, err
+	}
+
+	v := &cfgValidator{file: name}
+	value := v.object(root, configSchema, "")
+	if len(v.errs) > 0 {
+		return nil, errors.Join(v.errs...)
+	}
+
+	var cfg Config
+	data, err = json.Marshal(value)
+	if err == nil {
+		err = json.Unmarshal(data, &cfg)
+	}
+	if err != nil {
+		return nil, &ConfigError{File: name, Err: err}
+	}
+
+	v.check(root, &cfg)
+	if len(v.errs) > 0 {
+		return nil, errors.Join(v.errs...)
+	}
+	return &cfg, nil
+}
+
+// Options returns the options described by the config. Relative gitignore file
+// and root paths are resolved against dir.
+func (c *Config) Options(dir string) Options {
+	opts := Options{
+		Timeout:    time.Duration(c.Timeout),
+		Parallel:   c.Parallel,
+		SinglePass: c.SinglePass,
+		IgnoreCase: c.IgnoreCase,
+		Workers:    c.Workers,
+		CacheSize:  c.CacheSize,
+	}
+	if c.Regex != nil {
+		opts.Regex = &regex.Options{Patterns: c.Regex.Patterns, Literals: c.Regex.Literals, IgnoreCase: c.Regex.IgnoreCase}
+	}
+	if c.Glob != nil {
+		opts.Glob = &glob.Options{Patterns: c.Glob.Patterns, RawPatterns: c.Glob.RawPatterns, IgnoreCase: c.Glob.IgnoreCase}
+	}
+	if gi := c.GitIgnore; gi != nil {
+		opts.GitIgnore = &gitignore.Options{
+			Patterns:        gi.Patterns,
+			FilePath:        resolve(dir, gi.File),
+			Root:            resolve(dir, gi.Root),
+			ExcludeStandard: gi.ExcludeStandard,
+			IgnoreCase:      gi.IgnoreCase,
+		}
+		if gi.Engine == "wildmatch" {
+			opts.GitIgnore.Engine = gitignore.EngineWildmatch
+		}
+	}
+	return opts
+}
+
+func resolve(dir, path string) string {
+	if path == "" || filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(dir, path)
+}
+
+// check validates the values of a structurally valid config.
+func (v *cfgValidator) check(root *cfgNode, cfg *Config) {
+	if cfg.Regex == nil && cfg.Glob == nil && cfg.GitIgnore == nil {
+		v.fail(root, "", "atleast one matching strategy required: regex, glob or gitignore")
+	}
+
+	if cfg.Regex != nil {
+		node := root.get("regex")
+		if len(cfg.Regex.Patterns) == 0 {
+			v.fail(node, "regex", "atleast one pattern required")
+		}
+		if !cfg.Regex.Literals {
+			for i, p := range cfg.Regex.Patterns {
+				if _, err := regexp.Compile(p); err != nil {
+					v.fail(node.get("patterns").item(i), fmt.Sprintf("regex.patterns[%d]", i), "%s", err)
+				}
+			}
+		}
+	}
+
+	if cfg.Glob != nil {
+		node := root.get("glob")
+		for i, p := range cfg.Glob.Patterns {
+			if _, err := gobwas.Compile(p); err != nil {
+				v.fail(node.get("patterns").item(i), fmt.Sprintf("glob.patterns[%d]", i), "%s", err)
+			}
+		}
+	}
+
+	if gi := cfg.GitIgnore; gi != nil {
+		node := root.get("gitignore")
+		if len(gi.Patterns) == 0 && gi.File == "" && gi.Root == "" {
+			v.fail(node, "gitignore", "atleast one gitignore source required: patterns, file or root")
+		}
+		for i, p := range gi.Patterns {
+			opts := gitignore.Options{Patterns: []string{p}}
+			if gi.Engine == "wildmatch" {
+				opts.Engine = gitignore.EngineWildmatch
+			}
+			if _, err := gitignore.NewMatcher(opts); err != nil {
+				v.fail(node.get("patterns").item(i), fmt.Sprintf("gitignore.patterns[%d]", i), "%s", err)
+			}
+		}
+		if gi.ExcludeStandard && gi.Root == "" {
+			v.fail(node.get("exclude_standard"), "gitignore.exclude_standard", "root required")
+		}
+		if cfg.SinglePass && gi.Engine == "wildmatch" {
+			v.fail(node.get("engine"), "gitignore.engine", "wildmatch is not supported with single_pass")
+		}
+	}
+
+	if cfg.Timeout < 0 {
+		v.fail(root.get("timeout"), "timeout", "must not be negative")
+	}
+}
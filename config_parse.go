@@ -0,0 +1,410 @@
+package gopathignore
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	regexp "github.com/wasilibs/go-re2"
+	"gopkg.in/yaml.v3"
+)
+
+// cfgNode is a value of a config file with its position, whatever the format.
+type cfgNode struct {
+	kind cfgKind
+	// line and col are 1-based, zero if unknown.
+	line, col int
+	// value is nil, a string, a bool, an int64 or a float64 for scalars.
+	value  any
+	fields []cfgField // of objects, in file order
+	items  []*cfgNode // of arrays
+}
+
+type cfgKind uint8
+
+const (
+	cfgScalar cfgKind = iota
+	cfgObject
+	cfgArray
+)
+
+// cfgField is a key of an object, positioned at the key.
+type cfgField struct {
+	key       string
+	line, col int
+	node      *cfgNode
+}
+
+func (f cfgField) pos() *cfgNode {
+	return &cfgNode{line: f.line, col: f.col}
+}
+
+// get returns the value of key, nil if n is not an object with that key.
+func (n *cfgNode) get(key string) *cfgNode {
+	if n == nil {
+		return nil
+	}
+	for _, f := range n.fields {
+		if f.key == key {
+			return f.node
+		}
+	}
+	return nil
+}
+
+// item returns the i-th item, nil if n is not an array that long.
+func (n *cfgNode) item(i int) *cfgNode {
+	if n == nil || i >= len(n.items) {
+		return nil
+	}
+	return n.items[i]
+}
+
+func (n *cfgNode) int() (int64, bool) {
+	if n.kind != cfgScalar {
+		return 0, false
+	}
+	switch v := n.value.(type) {
+	case int64:
+		return v, true
+	case float64:
+		return int64(v), v == float64(int64(v))
+	}
+	return 0, false
+}
+
+func (n *cfgNode) kindName() string {
+	switch n.kind {
+	case cfgObject:
+		return "an object"
+	case cfgArray:
+		return "an array"
+	}
+	switch n.value.(type) {
+	case nil:
+		return "null"
+	case string:
+		return "a string"
+	case bool:
+		return "a boolean"
+	case int64, float64:
+		return "a number"
+	default:
+		return fmt.Sprintf("%T", n.value)
+	}
+}
+
+func parseYAML(data []byte) (*cfgNode, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, yamlError(err)
+	}
+	if len(doc.Content) == 0 {
+		return &cfgNode{kind: cfgObject, line: 1, col: 1}, nil
+	}
+	return yamlNode(doc.Content[0])
+}
+
+var yamlErrorLine = regexp.MustCompile(`^yaml: line (\d+): `)
+
+// yamlError turns a yaml syntax error into a ConfigError positioned at its line.
+func yamlError(err error) error {
+	msg := err.Error()
+	cerr := &ConfigError{}
+	if m := yamlErrorLine.FindStringSubmatch(msg); m != nil {
+		cerr.Line, _ = strconv.Atoi(m[1])
+		msg = msg[len(m[0]):]
+	}
+	cerr.Err = errors.New(strings.TrimPrefix(msg, "yaml: "))
+	return cerr
+}
+
+func yamlNode(y *yaml.Node) (*cfgNode, error) {
+	n := &cfgNode{line: y.Line, col: y.Column}
+	switch y.Kind {
+	case yaml.AliasNode:
+		return yamlNode(y.Alias)
+	case yaml.MappingNode:
+		n.kind = cfgObject
+		for i := 0; i+1 < len(y.Content); i += 2 {
+			k, v := y.Content[i], y.Content[i+1]
+			val, err := yamlNode(v)
+			if err != nil {
+				return nil, err
+			}
+			n.fields = append(n.fields, cfgField{key: k.Value, line: k.Line, col: k.Column, node: val})
+		}
+	case yaml.SequenceNode:
+		n.kind = cfgArray
+		for _, v := range y.Content {
+			item, err := yamlNode(v)
+			if err != nil {
+				return nil, err
+			}
+			n.items = append(n.items, item)
+		}
+	case yaml.ScalarNode:
+		var v any
+		if err := y.Decode(&v); err != nil {
+			return nil, &ConfigError{Line: y.Line, Column: y.Column, Err: err}
+		}
+		if i, ok := v.(int); ok {
+			v = int64(i)
+		}
+		n.value = v
+	default:
+		return nil, &ConfigError{Line: y.Line, Column: y.Column, Err: fmt.Errorf("unexpected yaml node")}
+	}
+	return n, nil
+}
+
+func parseJSON(data []byte) (*cfgNode, error) {
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+	p := &jsonParser{d: d, data: data}
+
+	n, err := p.value()
+	if err == nil {
+		if _, _, err = p.next(); err == nil {
+			err = p.errorf("unexpected data after the top-level value")
+		} else if err == io.EOF {
+			err = nil
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return n, nil
+}
+
+// jsonParser builds nodes from the tokens of a JSON document.
+type jsonParser struct {
+	d    *json.Decoder
+	data []byte
+	// off is the offset of the last token.
+	off int
+}
+
+// next returns the next token and the node positioned at it.
+func (p *jsonParser) next() (json.Token, *cfgNode, error) {
+	off := int(p.d.InputOffset())
+	tok, err := p.d.Token()
+	if err != nil {
+		var serr *json.SyntaxError
+		if errors.As(err, &serr) {
+			p.off = int(serr.Offset)
+			return nil, nil, p.errorf("%s", serr.Error())
+		}
+		if err == io.ErrUnexpectedEOF {
+			p.off = len(p.data)
+			return nil, nil, p.errorf("unexpected end of JSON input")
+		}
+		return nil, nil, err
+	}
+
+	// The offset is that of the end of the previous token, skip what separates
+	// them.
+	for off < len(p.data) && strings.IndexByte(" \t\r\n,:", p.data[off]) >= 0 {
+		off++
+	}
+	p.off = off
+	line, col := position(p.data, off)
+	return tok, &cfgNode{line: line, col: col}, nil
+}
+
+func (p *jsonParser) errorf(format string, args ...any) error {
+	line, col := position(p.data, p.off)
+	return &ConfigError{Line: line, Column: col, Err: fmt.Errorf(format, args...)}
+}
+
+func (p *jsonParser) value() (*cfgNode, error) {
+	tok, n, err := p.next()
+	if err == io.EOF {
+		return nil, p.errorf("empty JSON document")
+	} else if err != nil {
+		return nil, err
+	}
+
+	switch tok := tok.(type) {
+	case json.Delim:
+		if tok == '{' {
+			n.kind = cfgObject
+			for {
+				key, kn, err := p.next()
+				if err != nil {
+					return nil, err
+				}
+				if key == json.Delim('}') {
+					return n, nil
+				}
+				val, err := p.value()
+				if err != nil {
+					return nil, err
+				}
+				n.fields = append(n.fields, cfgField{key: key.(string), line: kn.line, col: kn.col, node: val})
+			}
+		}
+		n.kind = cfgArray
+		for p.d.More() {
+			item, err := p.value()
+			if err != nil {
+				return nil, err
+			}
+			n.items = append(n.items, item)
+		}
+		if _, _, err := p.next(); err != nil { // ']'
+			return nil, err
+		}
+	case json.Number:
+		if i, err := tok.Int64(); err == nil {
+			n.value = i
+		} else {
+			n.value, _ = tok.Float64()
+		}
+	default: // string, bool or nil
+		n.value = tok
+	}
+	return n, nil
+}
+
+// position returns the 1-based line and column of offset off of data.
+func position(data []byte, off int) (line, col int) {
+	off = min(off, len(data))
+	line = 1 + bytes.Count(data[:off], []byte{'\n'})
+	col = 1 + off - (bytes.LastIndexByte(data[:off], '\n') + 1)
+	return line, col
+}
+
+func parseTOML(data []byte) (*cfgNode, error) {
+	var doc map[string]any
+	md, err := toml.Decode(string(data), &doc)
+	if err != nil {
+		var perr toml.ParseError
+		if errors.As(err, &perr) {
+			return nil, &ConfigError{Line: perr.Position.Line, Column: perr.Position.Col, Err: errors.New(perr.Message)}
+		}
+		return nil, &ConfigError{Err: err}
+	}
+
+	// The keys are decoded into maps, order them as in the file.
+	order := map[string]int{}
+	for i, k := range md.Keys() {
+		order[k.String()] = i
+	}
+	t := &tomlLocator{lines: strings.Split(string(data), "\n"), keys: tomlKeyLines(data)}
+	return t.node(doc, nil, order), nil
+}
+
+// tomlLocator positions TOML values, which the decoder does not report, by
+// looking for their keys and values in the text.
+type tomlLocator struct {
+	lines []string
+	keys  map[string]int // line of dotted keys
+}
+
+func (t *tomlLocator) node(v any, path []string, order map[string]int) *cfgNode {
+	n := &cfgNode{line: t.line(path)}
+	switch v := v.(type) {
+	case map[string]any:
+		n.kind = cfgObject
+		for k, val := range v {
+			p := append(slices.Clip(path), k)
+			n.fields = append(n.fields, cfgField{key: k, line: t.line(p), node: t.node(val, p, order)})
+		}
+		slices.SortFunc(n.fields, func(a, b cfgField) int {
+			return order[toml.Key(append(slices.Clip(path), a.key)).String()] -
+				order[toml.Key(append(slices.Clip(path), b.key)).String()]
+		})
+	case []map[string]any:
+		n.kind = cfgArray
+		for _, item := range v {
+			n.items = append(n.items, t.node(item, path, order))
+		}
+	case []any:
+		n.kind = cfgArray
+		from := n.line
+		for _, item := range v {
+			in := t.node(item, path, order)
+			if s, ok := item.(string); ok && from > 0 {
+				if l := t.find(s, from); l > 0 {
+					in.line, from = l, l
+				}
+			}
+			n.items = append(n.items, in)
+		}
+	default:
+		n.value = v
+	}
+	return n
+}
+
+// line returns the line of the dotted key, or of its closest parent.
+func (t *tomlLocator) line(path []string) int {
+	for i := len(path); i > 0; i-- {
+		if l, ok := t.keys[strings.Join(path[:i], ".")]; ok {
+			return l
+		}
+	}
+	return 0
+}
+
+// find returns the first line from line from on that contains the string s,
+// quoted as a TOML basic or literal string, zero if there is none.
+func (t *tomlLocator) find(s string, from int) int {
+	basic, literal := strconv.Quote(s), "'"+s+"'"
+	for i := from - 1; i < len(t.lines); i++ {
+		if strings.Contains(t.lines[i], basic) || strings.Contains(t.lines[i], literal) {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+var (
+	tomlTable = regexp.MustCompile(`^\s*\[\[?\s*([^\[\]]+?)\s*\]\]?`)
+	tomlKey   = regexp.MustCompile(`^\s*([A-Za-z0-9_\-."' ]+?)\s*=`)
+)
+
+// tomlKeyLines returns the lines of the tables and keys of a TOML document by
+// their dotted path.
+func tomlKeyLines(data []byte) map[string]int {
+	keys := map[string]int{}
+	var table string
+	s := bufio.NewScanner(bytes.NewReader(data))
+	for line := 1; s.Scan(); line++ {
+		text := s.Text()
+		if m := tomlTable.FindStringSubmatch(text); m != nil {
+			table = tomlPath(m[1])
+			if _, ok := keys[table]; !ok {
+				keys[table] = line
+			}
+			continue
+		}
+		if m := tomlKey.FindStringSubmatch(text); m != nil {
+			key := tomlPath(m[1])
+			if table != "" {
+				key = table + "." + key
+			}
+			if _, ok := keys[key]; !ok {
+				keys[key] = line
+			}
+		}
+	}
+	return keys
+}
+
+// tomlPath normalizes a dotted TOML key, dropping quotes and spaces.
+func tomlPath(key string) string {
+	parts := strings.Split(key, ".")
+	for i, p := range parts {
+		parts[i] = strings.Trim(strings.TrimSpace(p), `"'`)
+	}
+	return strings.Join(parts, ".")
+}
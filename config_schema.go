@@ -0,0 +1,191 @@
+package gopathignore
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// schemaField describes a key of the config file. It is used both to validate
+// config files and to export the JSON Schema.
+type schemaField struct {
+	name string
+	// typ is a JSON Schema type: "object", "string", "boolean", "integer" or
+	// "array", which is always an array of strings.
+	typ    string
+	doc    string
+	format string // "duration" for durations such as "2s"
+	enum   []string
+	// nonNegative rejects negative integers.
+	nonNegative bool
+	fields      []schemaField
+}
+
+var configSchema = []schemaField{
+	{name: "regex", typ: "object", doc: "RE2 regular expressions matched anywhere in a path.", fields: []schemaField{
+		{name: "patterns", typ: "array", doc: "RE2 expressions."},
+		{name: "literals", typ: "boolean", doc: "Match the patterns as literal substrings instead of expressions."},
+		{name: "ignore_case", typ: "boolean", doc: "Match the patterns case-insensitively."},
+	}},
+	{name: "glob", typ: "object", doc: "Glob patterns matched against whole paths.", fields: []schemaField{
+		{name: "patterns", typ: "array", doc: "Glob patterns supporting *, ?, [...], {a,b} and **."},
+		{name: "raw_patterns", typ: "array", doc: "Paths matched literally."},
+		{name: "ignore_case", typ: "boolean", doc: "Match the patterns case-insensitively."},
+	}},
+	{name: "gitignore", typ: "object", doc: "Rules in gitignore format.", fields: []schemaField{
+		{name: "patterns", typ: "array", doc: "Gitignore lines."},
+		{name: "file", typ: "string", doc: "Ignore file to read, relative to the config file."},
+		{name: "root", typ: "string", doc: "Directory searched for .gitignore files, relative to the config file."},
+		{name: "exclude_standard", typ: "boolean", doc: "Also apply core.excludesFile and $GIT_DIR/info/exclude of the work tree at root."},
+		{name: "engine", typ: "string", doc: "Pattern matching implementation.", enum: []string{"regex", "wildmatch"}},
+		{name: "ignore_case", typ: "boolean", doc: "Match the rules case-insensitively, like git's core.ignoreCase."},
+	}},
+	{name: "timeout", typ: "string", format: "duration", doc: "Timeout of match operations, such as \"500ms\" or \"2s\"."},
+	{name: "parallel", typ: "boolean", doc: "Match the patterns of each strategy with a single RE2 set."},
+	{name: "single_pass", typ: "boolean", doc: "Match the rules of every strategy with a single RE2 set. Implies parallel."},
+	{name: "ignore_case", typ: "boolean", doc: "Match case-insensitively in every strategy."},
+	{name: "workers", typ: "integer", nonNegative: true, doc: "Number of goroutines batches of paths are matched by, the number of CPUs if 0."},
+	{name: "cache_size", typ: "integer", nonNegative: true, doc: "Number of match decisions kept in an LRU cache, 0 disables the cache."},
+}
+
+// ConfigSchema returns the JSON Schema of the config files read by LoadConfig.
+func ConfigSchema() ([]byte, error) {
+	schema := objectSchema(configSchema)
+	schema["$schema"] = "https://json-schema.org/draft/2020-12/schema"
+	schema["title"] = "go-path-ignore config"
+	return json.MarshalIndent(schema, "", "  ")
+}
+
+func objectSchema(fields []schemaField) map[string]any {
+	props := make(map[string]any, len(fields))
+	for _, f := range fields {
+		prop := map[string]any{"type": f.typ, "description": f.doc}
+		switch {
+		case f.typ == "object":
+			prop = objectSchema(f.fields)
+			prop["description"] = f.doc
+		case f.typ == "array":
+			prop["items"] = map[string]any{"type": "string"}
+		case f.format == "duration":
+			prop["pattern"] = `^(0|([0-9]+(\.[0-9]*)?(ns|us|µs|ms|s|m|h))+)$`
+		}
+		if f.enum != nil {
+			prop["enum"] = f.enum
+		}
+		if f.nonNegative {
+			prop["minimum"] = 0
+		}
+		props[f.name] = prop
+	}
+	return map[string]any{"type": "object", "properties": props, "additionalProperties": false}
+}
+
+// cfgValidator checks config nodes against the schema, collecting a ConfigError
+// per problem.
+type cfgValidator struct {
+	file string
+	errs []error
+}
+
+func (v *cfgValidator) fail(n *cfgNode, key, format string, args ...any) {
+	err := &ConfigError{File: v.file, Key: key, Err: fmt.Errorf(format, args...)}
+	if n != nil {
+		err.Line, err.Column = n.line, n.col
+	}
+	v.errs = append(v.errs, err)
+}
+
+// object returns the plain value of an object node, with the keys and values
+// the schema allows. Null values count as absent.
+func (v *cfgValidator) object(n *cfgNode, fields []schemaField, path string) map[string]any {
+	if n.kind != cfgObject {
+		v.fail(n, path, "expected an object, got %s", n.kindName())
+		return nil
+	}
+
+	res := make(map[string]any, len(n.fields))
+	for i, f := range n.fields {
+		key := f.key
+		if path != "" {
+			key = path + "." + f.key
+		}
+		j := slices.IndexFunc(fields, func(sf schemaField) bool { return sf.name == f.key })
+		if j < 0 {
+			v.fail(f.pos(), key, "unknown key")
+			continue
+		}
+		if slices.ContainsFunc(n.fields[:i], func(prev cfgField) bool { return prev.key == f.key }) {
+			v.fail(f.pos(), key, "duplicate key")
+			continue
+		}
+		if f.node.kind == cfgScalar && f.node.value == nil {
+			continue
+		}
+		if val, ok := v.value(f.node, fields[j], key); ok {
+			res[f.key] = val
+		}
+	}
+	return res
+}
+
+func (v *cfgValidator) value(n *cfgNode, f schemaField, path string) (any, bool) {
+	switch f.typ {
+	case "object":
+		res := v.object(n, f.fields, path)
+		return res, res != nil
+	case "array":
+		if n.kind != cfgArray {
+			v.fail(n, path, "expected an array of strings, got %s", n.kindName())
+			return nil, false
+		}
+		res := make([]string, 0, len(n.items))
+		for i, item := range n.items {
+			s, ok := item.value.(string)
+			if item.kind != cfgScalar || !ok {
+				v.fail(item, fmt.Sprintf("%s[%d]", path, i), "expected a string, got %s", item.kindName())
+				continue
+			}
+			res = append(res, s)
+		}
+		return res, true
+	case "string":
+		s, ok := n.value.(string)
+		if n.kind != cfgScalar || !ok {
+			v.fail(n, path, "expected a string, got %s", n.kindName())
+			return nil, false
+		}
+		if f.format == "duration" {
+			if _, err := time.ParseDuration(s); err != nil {
+				v.fail(n, path, "%s", strings.TrimPrefix(err.Error(), "time: "))
+				return nil, false
+			}
+		}
+		if f.enum != nil && !slices.Contains(f.enum, s) {
+			v.fail(n, path, "%q is not one of %q", s, f.enum)
+			return nil, false
+		}
+		return s, true
+	case "boolean":
+		b, ok := n.value.(bool)
+		if n.kind != cfgScalar || !ok {
+			v.fail(n, path, "expected a boolean, got %s", n.kindName())
+			return nil, false
+		}
+		return b, true
+	case "integer":
+		i, ok := n.int()
+		if !ok {
+			v.fail(n, path, "expected an integer, got %s", n.kindName())
+			return nil, false
+		}
+		if f.nonNegative && i < 0 {
+			v.fail(n, path, "must not be negative")
+			return nil, false
+		}
+		return i, true
+	default:
+		panic("unknown schema type " + f.typ)
+	}
+}
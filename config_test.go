@@ -0,0 +1,209 @@
+package gopathignore_test
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	gopathignore "github.com/vbhat161/go-path-ignore"
+	"github.com/vbhat161/go-path-ignore/match/gitignore"
+	"github.com/vbhat161/go-path-ignore/match/glob"
+	"github.com/vbhat161/go-path-ignore/match/regex"
+)
+
+const yamlConfig = `# ignore rules
+regex:
+  patterns: ['\.bak$']
+glob:
+  patterns:
+    - "*.tmp"
+  raw_patterns: ["[x].txt"]
+gitignore:
+  patterns: ["build/", "!build/keep"]
+  file: rules/.gitignore
+  root: .
+  engine: wildmatch
+  ignore_case: true
+timeout: 2s
+parallel: true
+workers: 4
+cache_size: 100
+`
+
+const jsonConfig = `{
+  "regex": {"patterns": ["\\.bak$"]},
+  "glob": {"patterns": ["*.tmp"], "raw_patterns": ["[x].txt"]},
+  "gitignore": {
+    "patterns": ["build/", "!build/keep"],
+    "file": "rules/.gitignore",
+    "root": ".",
+    "engine": "wildmatch",
+    "ignore_case": true
+  },
+  "timeout": "2s",
+  "parallel": true,
+  "workers": 4,
+  "cache_size": 100
+}`
+
+const tomlConfig = `timeout = "2s"
+parallel = true
+workers = 4
+cache_size = 100
+
+[regex]
+patterns = ['\.bak$']
+
+[glob]
+patterns = ["*.tmp"]
+raw_patterns = ["[x].txt"]
+
+[gitignore]
+patterns = [
+  "build/",
+  "!build/keep",
+]
+file = "rules/.gitignore"
+root = "."
+engine = "wildmatch"
+ignore_case = true
+`
+
+func TestLoadConfig(t *testing.T) {
+	dir := t.TempDir()
+	want := gopathignore.Options{
+		Regex: &regex.Options{Patterns: []string{`\.bak$`}},
+		Glob:  &glob.Options{Patterns: []string{"*.tmp"}, RawPatterns: []string{"[x].txt"}},
+		GitIgnore: &gitignore.Options{
+			Patterns:   []string{"build/", "!build/keep"},
+			FilePath:   filepath.Join(dir, "rules", ".gitignore"),
+			Root:       dir,
+			Engine:     gitignore.EngineWildmatch,
+			IgnoreCase: true,
+		},
+		Timeout:   2 * time.Second,
+		Parallel:  true,
+		Workers:   4,
+		CacheSize: 100,
+	}
+
+	for name, data := range map[string]string{"ignore.yaml": yamlConfig, "ignore.json": jsonConfig, "ignore.toml": tomlConfig} {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(data), 0o644))
+		opts, err := gopathignore.LoadConfig(path)
+		require.NoError(t, err, name)
+		require.Equal(t, want, opts, name)
+	}
+}
+
+func TestParseConfigErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want []gopathignore.ConfigError
+	}{
+		{
+			name: "c.yaml",
+			data: "regex:\n  patterns:\n    - ok\n    - '('\nglob:\n  patterns: [1]\ntimeout: soon\nworkers: -1\nextra: true\n",
+			want: []gopathignore.ConfigError{
+				{Line: 6, Column: 14, Key: "glob.patterns[0]"},
+				{Line: 7, Column: 10, Key: "timeout"},
+				{Line: 8, Column: 10, Key: "workers"},
+				{Line: 9, Column: 1, Key: "extra"},
+			},
+		},
+		{
+			name: "c.yaml",
+			data: "regex:\n  patterns:\n    - ok\n    - '('\n",
+			want: []gopathignore.ConfigError{{Line: 4, Column: 7, Key: "regex.patterns[1]"}},
+		},
+		{
+			name: "c.json",
+			data: "{\n  \"gitignore\": {\"engine\": \"fnmatch\", \"patterns\": [\"*.log\"]},\n  \"parallel\": \"yes\"\n}",
+			want: []gopathignore.ConfigError{
+				{Line: 2, Column: 27, Key: "gitignore.engine"},
+				{Line: 3, Column: 15, Key: "parallel"},
+			},
+		},
+		{
+			name: "c.json",
+			data: "{\n  \"glob\": {\"patterns\": [\"*.go\",\n  ]}\n}",
+			want: []gopathignore.ConfigError{{Line: 2, Column: 32}},
+		},
+		{
+			name: "c.toml",
+			data: "[glob]\npatterns = [\n  \"*.go\",\n  \"[\",\n]\n",
+			want: []gopathignore.ConfigError{{Line: 4, Key: "glob.patterns[1]"}},
+		},
+		{
+			name: "c.yaml",
+			data: "gitignore:\n  patterns:\n    - '*.log'\n    - 'a['\n",
+			want: []gopathignore.ConfigError{{Line: 4, Column: 7, Key: "gitignore.patterns[1]"}},
+		},
+		{
+			name: "c.toml",
+			data: "[gitignore]\npatterns = [\"a\"]\nunknown = 1\n",
+			want: []gopathignore.ConfigError{{Line: 3, Key: "gitignore.unknown"}},
+		},
+		{
+			name: "c.yaml",
+			data: "parallel: true\n",
+			want: []gopathignore.ConfigError{{Line: 1, Column: 1}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := gopathignore.ParseConfig(tt.name, []byte(tt.data))
+			require.Error(t, err)
+
+			var errs []error
+			if joined, ok := err.(interface{ Unwrap() []error }); ok {
+				errs = joined.Unwrap()
+			} else {
+				errs = []error{err}
+			}
+			require.Len(t, errs, len(tt.want), err.Error())
+			for i, e := range errs {
+				var cerr *gopathignore.ConfigError
+				require.True(t, errors.As(e, &cerr), e.Error())
+				require.Equal(t, tt.name, cerr.File)
+				require.Equal(t, tt.want[i].Line, cerr.Line, e.Error())
+				require.Equal(t, tt.want[i].Column, cerr.Column, e.Error())
+				require.Equal(t, tt.want[i].Key, cerr.Key, e.Error())
+			}
+		})
+	}
+}
+
+func TestConfigSchema(t *testing.T) {
+	data, err := gopathignore.ConfigSchema()
+	require.NoError(t, err)
+
+	var schema struct {
+		Type       string `json:"type"`
+		Properties map[string]struct {
+			Type       string         `json:"type"`
+			Pattern    string         `json:"pattern"`
+			Properties map[string]any `json:"properties"`
+		} `json:"properties"`
+	}
+	require.NoError(t, json.Unmarshal(data, &schema))
+	require.Equal(t, "object", schema.Type)
+	require.Equal(t, "object", schema.Properties["gitignore"].Type)
+	require.Contains(t, schema.Properties["gitignore"].Properties, "exclude_standard")
+	require.Equal(t, "string", schema.Properties["timeout"].Type)
+	duration := regexp.MustCompile(schema.Properties["timeout"].Pattern)
+	for _, d := range []string{"0", "2s", "1h30m", "1.5s"} {
+		require.True(t, duration.MatchString(d), d)
+	}
+	for _, d := range []string{"", "2", "soon", "-1s"} {
+		require.False(t, duration.MatchString(d), d)
+	}
+	require.Equal(t, "integer", schema.Properties["cache_size"].Type)
+}
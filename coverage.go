@@ -0,0 +1,213 @@
+package gopathignore
+
+import (
+	"context"
+	"io/fs"
+	"maps"
+	"slices"
+
+	"github.com/vbhat161/go-path-ignore/match"
+	"github.com/vbhat161/go-path-ignore/match/gitignore"
+	"github.com/vbhat161/go-path-ignore/match/glob"
+	"github.com/vbhat161/go-path-ignore/match/regex"
+)
+
+// RuleCoverage is how a rule fared against the paths added to a Coverage.
+type RuleCoverage struct {
+	Type   match.Type
+	Source match.Source
+	// Hits is the number of paths the rule matched, Decisive the number of paths
+	// it decided about.
+	Hits     int
+	Decisive int
+	// CoveredBy is the index in CoverageReport.Rules of an earlier rule that
+	// matched every path this one did with the same effect, so that removing this
+	// rule would not have changed any result. It is -1 if there is none or the
+	// rule never matched.
+	CoveredBy int
+}
+
+// CoverageReport lists every rule of a PathIgnore, in evaluation order, with its
+// coverage.
+type CoverageReport struct {
+	// Paths is the number of paths evaluated.
+	Paths int
+	Rules []RuleCoverage
+}
+
+// Unused returns the rules that matched no path.
+func (r CoverageReport) Unused() []RuleCoverage {
+	var res []RuleCoverage
+	for _, rc := range r.Rules {
+		if rc.Hits == 0 {
+			res = append(res, rc)
+		}
+	}
+	return res
+}
+
+// Covered returns the rules whose matches were all matched by an earlier rule
+// too.
+func (r CoverageReport) Covered() []RuleCoverage {
+	var res []RuleCoverage
+	for _, rc := range r.Rules {
+		if rc.CoveredBy >= 0 {
+			res = append(res, rc)
+		}
+	}
+	return res
+}
+
+// Coverage counts how often the rules of a PathIgnore match a set of paths, to
+// find rules that are dead weight. It is not safe for concurrent use.
+type Coverage struct {
+	pi    *PathIgnore
+	paths int
+	rules []RuleCoverage
+	index map[ruleKey]int
+	// earlier holds, for every rule that matched, the earlier rules that covered
+	// it for every path it matched, see covering.
+	earlier []map[int]bool
+}
+
+// ruleKey identifies a rule by its source.
+type ruleKey struct {
+	typ  match.Type
+	file string
+	line int
+	pat  string
+}
+
+// NewCoverage returns an empty Coverage of the rules of pi.
+func (pi *PathIgnore) NewCoverage() *Coverage {
+	c := &Coverage{pi: pi, index: map[ruleKey]int{}}
+	add := func(typ match.Type, src match.Source) {
+		c.index[ruleKey{typ, src.File, src.Line, src.Pattern}] = len(c.rules)
+		c.rules = append(c.rules, RuleCoverage{Type: typ, Source: src, CoveredBy: -1})
+	}
+	for _, m := range pi.strategies {
+		switch m := m.(type) {
+		case *regex.Matcher:
+			for i, p := range m.State().Patterns {
+				add(match.Regex, match.Source{Pattern: p, Line: i + 1})
+			}
+		case *gitignore.Matcher:
+			for _, layer := range m.State().Layers {
+				for _, r := range layer.Rules {
+					add(match.GitIgnore, match.Source{Pattern: r.Source, File: r.File, Line: r.Line, Negate: r.Negate})
+				}
+			}
+		case *glob.Matcher:
+			s := m.State()
+			for i, p := range s.Patterns {
+				add(match.Glob, match.Source{Pattern: p, Line: s.Lines[i]})
+			}
+		}
+	}
+	c.earlier = make([]map[int]bool, len(c.rules))
+	return c
+}
+
+// Add evaluates path, which is a directory if it has a trailing slash, and
+// returns its explanation.
+func (c *Coverage) Add(ctx context.Context, path string) (match.Explanation, error) {
+	exp, err := c.pi.Explain(ctx, path)
+	if err != nil {
+		return exp, err
+	}
+	c.paths++
+
+	// A gitignore rule may match several parent directories of a path, it counts
+	// once.
+	decisive := map[int]bool{}
+	for _, h := range exp.Hits {
+		i, ok := c.index[ruleKey{h.Type, h.File, h.Line, h.Pattern}]
+		if !ok {
+			continue
+		}
+		decisive[i] = decisive[i] || h.Decisive
+	}
+	hits := slices.Sorted(maps.Keys(decisive))
+	for n, i := range hits {
+		rc := &c.rules[i]
+		rc.Hits++
+		if decisive[i] {
+			rc.Decisive++
+		}
+
+		covering := c.covering(hits[:n], i, exp.Result)
+		if rc.Hits == 1 {
+			c.earlier[i] = covering
+			continue
+		}
+		for j := range c.earlier[i] {
+			if !covering[j] {
+				delete(c.earlier[i], j)
+			}
+		}
+	}
+	return exp, nil
+}
+
+// covering returns the rules among the earlier hits of a path that make rule i
+// redundant for it: rules of an earlier strategy that ignored the path, and
+// gitignore rules of the same kind with no rule of the opposite kind between
+// them and i.
+func (c *Coverage) covering(earlier []int, i int, res match.MatchInfo) map[int]bool {
+	r := c.rules[i]
+	covering := map[int]bool{}
+	for n, j := range earlier {
+		o := c.rules[j]
+		if o.Type != r.Type {
+			if res.Ok() && res.Type() == o.Type {
+				covering[j] = true
+			}
+			continue
+		}
+		if o.Source.Negate != r.Source.Negate {
+			continue
+		}
+		if !slices.ContainsFunc(earlier[n+1:], func(k int) bool { return c.rules[k].Source.Negate != r.Source.Negate }) {
+			covering[j] = true
+		}
+	}
+	return covering
+}
+
+// AddFS evaluates the entries of the file tree of fsys rooted at root, with
+// slash separated paths relative to fsys. Like WalkDir, it does not descend into
+// ignored directories, whose content the rules cannot affect, and it skips .git
+// directories. Entries that cannot be read are passed to onErr, which may be nil
+// to stop at the first.
+func (c *Coverage) AddFS(ctx context.Context, fsys fs.FS, root string, onErr ErrFunc) error {
+	return walkTree(fsys, root, onErr, func(path string, d fs.DirEntry) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if d.IsDir() {
+			path += "/"
+		}
+		exp, err := c.Add(ctx, path)
+		if err != nil {
+			return err
+		}
+		if d.IsDir() && exp.Result.Ok() {
+			return fs.SkipDir
+		}
+		return nil
+	})
+}
+
+// Report returns the coverage of the paths added so far.
+func (c *Coverage) Report() CoverageReport {
+	r := CoverageReport{Paths: c.paths, Rules: make([]RuleCoverage, len(c.rules))}
+	copy(r.Rules, c.rules)
+	for i, earlier := range c.earlier {
+		for j := range earlier {
+			if r.Rules[i].CoveredBy < 0 || j < r.Rules[i].CoveredBy {
+				r.Rules[i].CoveredBy = j
+			}
+		}
+	}
+	return r
+}
@@ -0,0 +1,118 @@
+package gopathignore_test
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/require"
+	gopathignore "github.com/vbhat161/go-path-ignore"
+	"github.com/vbhat161/go-path-ignore/match/gitignore"
+	"github.com/vbhat161/go-path-ignore/match/glob"
+	"github.com/vbhat161/go-path-ignore/match/regex"
+)
+
+// coverage summarizes the coverage of a rule as pattern, hits, decisive hits and
+// the pattern of the covering rule.
+type coverage struct {
+	pattern   string
+	hits      int
+	decisive  int
+	coveredBy string
+}
+
+func summarizeCoverage(r gopathignore.CoverageReport) []coverage {
+	var res []coverage
+	for _, rc := range r.Rules {
+		c := coverage{pattern: rc.Source.Pattern, hits: rc.Hits, decisive: rc.Decisive}
+		if rc.CoveredBy >= 0 {
+			c.coveredBy = r.Rules[rc.CoveredBy].Source.Pattern
+		}
+		res = append(res, c)
+	}
+	return res
+}
+
+func TestCoverage(t *testing.T) {
+	for _, singlePass := range []bool{false, true} {
+		pi, err := gopathignore.New(gopathignore.Options{
+			Regex: &regex.Options{Patterns: []string{`\.bak$`, `^never/`}},
+			GitIgnore: &gitignore.Options{Patterns: []string{
+				"*.log", "!keep.log", "debug.log", "build/", "build/*.o", "*.tmp", "tmp/*.tmp", "*.bak", "unused",
+			}},
+			Glob:       &glob.Options{Patterns: []string{"*.cache", "**/x.cache"}},
+			SinglePass: singlePass,
+		})
+		require.NoError(t, err)
+
+		c := pi.NewCoverage()
+		for _, path := range []string{
+			"a.log", "keep.log", "debug.log", "build/", "build/a.o", "a.tmp", "tmp/b.tmp", "c.bak", "x.cache", "y/x.cache", "main.go",
+		} {
+			_, err := c.Add(context.Background(), path)
+			require.NoError(t, err)
+		}
+
+		r := c.Report()
+		require.Equal(t, 11, r.Paths)
+		require.Equal(t, []coverage{
+			{pattern: `\.bak$`, hits: 1, decisive: 1},
+			{pattern: `^never/`},
+			{pattern: "*.log", hits: 3, decisive: 1},
+			{pattern: "!keep.log", hits: 1, decisive: 1},
+			// The negation only applies to keep.log.
+			{pattern: "debug.log", hits: 1, decisive: 1, coveredBy: "*.log"},
+			{pattern: "build/", hits: 2, decisive: 2},
+			// build/a.o is excluded by its parent directory.
+			{pattern: "build/*.o"},
+			{pattern: "*.tmp", hits: 2, decisive: 1},
+			{pattern: "tmp/*.tmp", hits: 1, decisive: 1, coveredBy: "*.tmp"},
+			{pattern: "*.bak", hits: 1, coveredBy: `\.bak$`},
+			{pattern: "unused"},
+			{pattern: "*.cache", hits: 2, decisive: 2},
+			{pattern: "**/x.cache", hits: 1, coveredBy: "*.cache"},
+		}, summarizeCoverage(r), "single pass %v", singlePass)
+
+		var unused []string
+		for _, rc := range r.Unused() {
+			unused = append(unused, rc.Source.Pattern)
+		}
+		require.Equal(t, []string{`^never/`, "build/*.o", "unused"}, unused)
+		require.Len(t, r.Covered(), 4)
+	}
+}
+
+func TestCoverageFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"main.go":               {},
+		"debug.log":             {},
+		"node_modules/a/b.js":   {},
+		"node_modules/a/c.js":   {},
+		"src/app.go":            {},
+		"src/gen/x.go":          {},
+		"src/gen/y.go":          {},
+		"src/gen/sub/z.go":      {},
+		"docs/node_modules/x.d": {},
+		".git/HEAD":             {},
+		".git/objects/x.go":     {},
+	}
+	pi, err := gopathignore.New(gopathignore.Options{
+		GitIgnore: &gitignore.Options{Patterns: []string{"node_modules/", "**/node_modules/**/*.js", "*.log", "src/gen/", "*.go"}},
+	})
+	require.NoError(t, err)
+
+	c := pi.NewCoverage()
+	require.NoError(t, c.AddFS(context.Background(), fsys, ".", nil))
+	r := c.Report()
+	// Ignored directories are not descended into, nor is .git.
+	require.Equal(t, 8, r.Paths)
+	require.Equal(t, []coverage{
+		{pattern: "node_modules/", hits: 2, decisive: 2},
+		{pattern: "**/node_modules/**/*.js"},
+		{pattern: "*.log", hits: 1, decisive: 1},
+		{pattern: "src/gen/", hits: 1, decisive: 1},
+		{pattern: "*.go", hits: 2, decisive: 2},
+	}, summarizeCoverage(r))
+	require.True(t, slices.IndexFunc(r.Unused(), func(rc gopathignore.RuleCoverage) bool { return rc.Source.Line == 2 }) >= 0)
+}
@@ -0,0 +1,171 @@
+package gopathignore
+
+import (
+	"context"
+	"io/fs"
+	"strings"
+
+	"github.com/vbhat161/go-path-ignore/match"
+)
+
+// Change is a path that is ignored by one of two rule sets but not the other.
+type Change struct {
+	// Path is the path, with a trailing slash for directories.
+	Path string
+	// Old and New are the results of the old and the new rules for the path. The
+	// one that did not ignore the path is match.NoMatch.
+	Old, New match.MatchInfo
+	// Negation is the negated rule that decided to include the path on the side
+	// that does not ignore it, nil if no rule re-included it there.
+	Negation *match.Hit
+}
+
+// Ignored reports whether the new rules ignore the path, otherwise they include
+// a path the old rules ignored.
+func (c Change) Ignored() bool {
+	return c.New.Ok()
+}
+
+// DiffReport lists the paths added to a Diff whose status changed, in the order
+// they were added.
+type DiffReport struct {
+	// Paths is the number of paths evaluated.
+	Paths    int
+	Ignored  []Change
+	Included []Change
+}
+
+// Diff compares two rule sets on a set of paths, to review the effect of editing
+// rules. It is not safe for concurrent use.
+type Diff struct {
+	old, new diffSide
+	report   DiffReport
+}
+
+// diffSide is a rule set of a Diff. While walking a tree, prefix is the last
+// directory it ignored and pruned the match of that directory, which applies to
+// the entries below it.
+type diffSide struct {
+	pi     *PathIgnore
+	prefix string
+	pruned match.MatchInfo
+}
+
+// NewDiff returns an empty Diff of the rules described by oldOpts and newOpts.
+func NewDiff(oldOpts, newOpts Options) (*Diff, error) {
+	oldPI, err := New(oldOpts)
+	if err != nil {
+		return nil, err
+	}
+	newPI, err := New(newOpts)
+	if err != nil {
+		return nil, err
+	}
+	return &Diff{old: diffSide{pi: oldPI}, new: diffSide{pi: newPI}}, nil
+}
+
+// Add evaluates path, which is a directory if it has a trailing slash, with both
+// rule sets. It returns the change and true if their results differ.
+func (d *Diff) Add(ctx context.Context, path string) (Change, bool, error) {
+	oldInfo, err := d.old.pi.Match2(ctx, path)
+	if err != nil {
+		return Change{}, false, err
+	}
+	newInfo, err := d.new.pi.Match2(ctx, path)
+	if err != nil {
+		return Change{}, false, err
+	}
+	c := Change{Path: path, Old: oldInfo, New: newInfo}
+	changed, err := d.add(ctx, &c)
+	return c, changed, err
+}
+
+// add records c and reports whether the status of its path changed. A change is
+// completed with the negation that included the path, if any.
+func (d *Diff) add(ctx context.Context, c *Change) (bool, error) {
+	d.report.Paths++
+	if c.Old.Ok() == c.New.Ok() {
+		return false, nil
+	}
+
+	including := d.new.pi
+	if c.Ignored() {
+		including = d.old.pi
+	}
+	exp, err := including.Explain(ctx, c.Path)
+	if err != nil {
+		return false, err
+	}
+	// Without a match, only a negation can be decisive.
+	for _, h := range exp.Hits {
+		if h.Decisive {
+			c.Negation = &h
+		}
+	}
+
+	if c.Ignored() {
+		d.report.Ignored = append(d.report.Ignored, *c)
+	} else {
+		d.report.Included = append(d.report.Included, *c)
+	}
+	return true, nil
+}
+
+// AddFS evaluates the entries of the file tree of fsys rooted at root, with
+// slash separated paths relative to fsys. Directories ignored by both rule sets
+// are not descended into. Below a directory ignored by only one of them, the
+// entries are reported with the match of that directory for that side, as
+// walking with its rules would never reach them. Like Coverage.AddFS, it skips
+// .git directories and passes entries that cannot be read to onErr.
+func (d *Diff) AddFS(ctx context.Context, fsys fs.FS, root string, onErr ErrFunc) error {
+	d.old.prefix, d.new.prefix = "", ""
+	return walkTree(fsys, root, onErr, func(path string, de fs.DirEntry) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		oldInfo, err := d.old.match(ctx, path, de.IsDir())
+		if err != nil {
+			return err
+		}
+		newInfo, err := d.new.match(ctx, path, de.IsDir())
+		if err != nil {
+			return err
+		}
+		c := Change{Path: path, Old: oldInfo, New: newInfo}
+		if de.IsDir() {
+			c.Path += "/"
+		}
+		if _, err := d.add(ctx, &c); err != nil {
+			return err
+		}
+		if de.IsDir() && oldInfo.Ok() && newInfo.Ok() {
+			return fs.SkipDir
+		}
+		return nil
+	})
+}
+
+// match returns the result of the side for an entry of the tree walked by AddFS.
+func (s *diffSide) match(ctx context.Context, path string, isDir bool) (match.MatchInfo, error) {
+	if s.prefix != "" && strings.HasPrefix(path, s.prefix) {
+		return s.pruned, nil
+	}
+	s.prefix = ""
+	info, err := s.pi.MatchEntry(ctx, path, isDir)
+	if err != nil {
+		return nil, err
+	}
+	if isDir && info.Ok() {
+		s.prefix, s.pruned = path+"/", info
+	}
+	return info, nil
+}
+
+// Report returns the changes among the paths added so far.
+func (d *Diff) Report() DiffReport {
+	r := d.report
+	r.Ignored = append([]Change(nil), r.Ignored...)
+	r.Included = append([]Change(nil), r.Included...)
+	return r
+}
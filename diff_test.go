@@ -0,0 +1,138 @@
+package gopathignore_test
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/require"
+	gopathignore "github.com/vbhat161/go-path-ignore"
+	"github.com/vbhat161/go-path-ignore/match"
+	"github.com/vbhat161/go-path-ignore/match/gitignore"
+	"github.com/vbhat161/go-path-ignore/match/glob"
+)
+
+// change summarizes a change as path and the patterns of both sides.
+type change struct {
+	path     string
+	old, new string
+}
+
+func summarizeChanges(changes []gopathignore.Change) []change {
+	var res []change
+	for _, c := range changes {
+		res = append(res, change{c.Path, c.Old.Source().Pattern, c.New.Source().Pattern})
+	}
+	return res
+}
+
+func TestDiff(t *testing.T) {
+	d, err := gopathignore.NewDiff(
+		gopathignore.Options{GitIgnore: &gitignore.Options{Patterns: []string{"*.log", "build/"}}},
+		gopathignore.Options{
+			GitIgnore: &gitignore.Options{Patterns: []string{"*.log", "!keep.log", "build/"}},
+			Glob:      &glob.Options{Patterns: []string{"*.tmp"}},
+		},
+	)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	for _, path := range []string{"a.log", "keep.log", "build/", "build/keep.log", "a.tmp", "main.go"} {
+		c, changed, err := d.Add(ctx, path)
+		require.NoError(t, err)
+		require.Equal(t, path == "keep.log" || path == "a.tmp", changed, path)
+		require.Equal(t, path, c.Path)
+	}
+
+	r := d.Report()
+	require.Equal(t, 6, r.Paths)
+	require.Equal(t, []change{{"a.tmp", "", "*.tmp"}}, summarizeChanges(r.Ignored))
+	require.Equal(t, match.Glob, r.Ignored[0].New.Type())
+	require.True(t, r.Ignored[0].Ignored())
+	require.Nil(t, r.Ignored[0].Negation)
+	require.Equal(t, []change{{"keep.log", "*.log", ""}}, summarizeChanges(r.Included))
+	require.False(t, r.Included[0].Ignored())
+	// The new rules include keep.log with a negation.
+	require.NotNil(t, r.Included[0].Negation)
+	require.Equal(t, match.GitIgnore, r.Included[0].Negation.Type)
+	require.Equal(t, "!keep.log", r.Included[0].Negation.Pattern)
+	require.Equal(t, 2, r.Included[0].Negation.Line)
+	require.True(t, r.Included[0].Negation.Negate)
+
+	_, err = gopathignore.NewDiff(gopathignore.Options{}, gopathignore.Options{Glob: &glob.Options{Patterns: []string{"["}}})
+	require.Error(t, err)
+}
+
+func TestDiffFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"main.go":           {},
+		"dist/app.js":       {},
+		"dist/sub/x.js":     {},
+		"vendor/a/a.go":     {},
+		"node_modules/x.js": {},
+		"out/a.o":           {},
+		".git/HEAD":         {},
+		".git/x.o":          {},
+	}
+	d, err := gopathignore.NewDiff(
+		gopathignore.Options{GitIgnore: &gitignore.Options{Patterns: []string{"dist/", "node_modules/", "vendor/"}}},
+		gopathignore.Options{
+			GitIgnore: &gitignore.Options{Patterns: []string{"node_modules/", "*.o"}},
+			Glob:      &glob.Options{Patterns: []string{"vendor"}},
+		},
+	)
+	require.NoError(t, err)
+	require.NoError(t, d.AddFS(context.Background(), fsys, ".", nil))
+
+	r := d.Report()
+	// node_modules is ignored on both sides and not descended into, vendor too
+	// as the glob matches the directory. .git is skipped.
+	require.Equal(t, 9, r.Paths)
+	require.Equal(t, []change{{"out/a.o", "", "*.o"}}, summarizeChanges(r.Ignored))
+	// Entries below dist are reported with the match of the directory.
+	require.Equal(t, []change{
+		{"dist/", "dist/", ""},
+		{"dist/app.js", "dist/", ""},
+		{"dist/sub/", "dist/", ""},
+		{"dist/sub/x.js", "dist/", ""},
+	}, summarizeChanges(r.Included))
+}
+
+// errFS is a file system whose directory bad cannot be read.
+type errFS struct {
+	fstest.MapFS
+	bad string
+}
+
+func (f errFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	if name == f.bad {
+		return nil, errors.New("permission denied")
+	}
+	return f.MapFS.ReadDir(name)
+}
+
+func TestDiffFSErr(t *testing.T) {
+	fsys := errFS{MapFS: fstest.MapFS{"a.log": {}, "secret/b.log": {}, "z.log": {}}, bad: "secret"}
+	newDiff := func() *gopathignore.Diff {
+		d, err := gopathignore.NewDiff(
+			gopathignore.Options{GitIgnore: &gitignore.Options{Patterns: []string{"*.log"}}},
+			gopathignore.Options{GitIgnore: &gitignore.Options{Patterns: []string{"a.log"}}},
+		)
+		require.NoError(t, err)
+		return d
+	}
+
+	require.ErrorContains(t, newDiff().AddFS(context.Background(), fsys, ".", nil), "permission denied")
+
+	// Unreadable entries can be skipped.
+	var skipped []string
+	d := newDiff()
+	require.NoError(t, d.AddFS(context.Background(), fsys, ".", func(path string, err error) error {
+		skipped = append(skipped, path)
+		return nil
+	}))
+	require.Equal(t, []string{"secret"}, skipped)
+	require.Equal(t, []change{{"z.log", "*.log", ""}}, summarizeChanges(d.Report().Included))
+}
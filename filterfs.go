@@ -0,0 +1,226 @@
+package gopathignore
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"path"
+)
+
+// FilterFS returns a file system that hides the entries of fsys ignored by pi, as
+// well as everything below ignored directories. Ignored entries look like they do
+// not exist: opening them returns fs.ErrNotExist and they are left out of
+// directory listings and Glob results.
+//
+// Paths are matched slash separated and relative to the root of fsys, also for
+// file systems returned by Sub.
+func FilterFS(fsys fs.FS, pi *PathIgnore) fs.FS {
+	return &filterFS{fsys: fsys, pi: pi}
+}
+
+var (
+	_ fs.ReadDirFS = (*filterFS)(nil)
+	_ fs.StatFS    = (*filterFS)(nil)
+	_ fs.GlobFS    = (*filterFS)(nil)
+	_ fs.SubFS     = (*filterFS)(nil)
+)
+
+type filterFS struct {
+	fsys fs.FS
+	pi   *PathIgnore
+	// dir is the path of fsys relative to the root the patterns apply to, empty
+	// unless fsys was obtained with Sub.
+	dir string
+}
+
+func (f *filterFS) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+	if err := f.checkParents("open", name); err != nil {
+		return nil, err
+	}
+
+	file, err := f.fsys.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	if err := f.check("open", name, info.IsDir()); err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	if dir, ok := file.(fs.ReadDirFile); ok && info.IsDir() {
+		return &filterDir{ReadDirFile: dir, fsys: f, name: name}, nil
+	}
+	return file, nil
+}
+
+func (f *filterFS) Stat(name string) (fs.FileInfo, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "stat", Path: name, Err: fs.ErrInvalid}
+	}
+	if err := f.checkParents("stat", name); err != nil {
+		return nil, err
+	}
+
+	info, err := fs.Stat(f.fsys, name)
+	if err != nil {
+		return nil, err
+	}
+	if err := f.check("stat", name, info.IsDir()); err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+func (f *filterFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrInvalid}
+	}
+	if err := f.checkParents("readdir", name); err != nil {
+		return nil, err
+	}
+	if err := f.check("readdir", name, true /*isDir*/); err != nil {
+		return nil, err
+	}
+
+	entries, err := fs.ReadDir(f.fsys, name)
+	if err != nil {
+		return nil, err
+	}
+	return f.filter(name, entries)
+}
+
+func (f *filterFS) Glob(pattern string) ([]string, error) {
+	matches, err := fs.Glob(f.fsys, pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	visible := matches[:0]
+	for _, m := range matches {
+		if _, err := f.Stat(m); errors.Is(err, fs.ErrNotExist) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		visible = append(visible, m)
+	}
+	return visible, nil
+}
+
+func (f *filterFS) Sub(dir string) (fs.FS, error) {
+	if !fs.ValidPath(dir) {
+		return nil, &fs.PathError{Op: "sub", Path: dir, Err: fs.ErrInvalid}
+	}
+	if dir == "." {
+		return f, nil
+	}
+	if err := f.checkParents("sub", dir); err != nil {
+		return nil, err
+	}
+	if err := f.check("sub", dir, true /*isDir*/); err != nil {
+		return nil, err
+	}
+
+	sub, err := fs.Sub(f.fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+	return &filterFS{fsys: sub, pi: f.pi, dir: path.Join(f.dir, dir)}, nil
+}
+
+// filter drops the ignored entries of directory dir.
+func (f *filterFS) filter(dir string, entries []fs.DirEntry) ([]fs.DirEntry, error) {
+	visible := make([]fs.DirEntry, 0, len(entries))
+	for _, e := range entries {
+		if ignored, err := f.ignored(path.Join(dir, e.Name()), e.IsDir()); err != nil {
+			return nil, err
+		} else if !ignored {
+			visible = append(visible, e)
+		}
+	}
+	return visible, nil
+}
+
+// checkParents returns an fs.ErrNotExist error if a parent directory of name is
+// ignored.
+func (f *filterFS) checkParents(op, name string) error {
+	for i := 0; i < len(name); i++ {
+		if name[i] != '/' {
+			continue
+		}
+		if err := f.check(op, name[:i], true /*isDir*/); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// check returns an fs.ErrNotExist error if name is ignored.
+func (f *filterFS) check(op, name string, isDir bool) error {
+	ignored, err := f.ignored(name, isDir)
+	if err != nil {
+		return &fs.PathError{Op: op, Path: name, Err: err}
+	}
+	if ignored {
+		return &fs.PathError{Op: op, Path: name, Err: fs.ErrNotExist}
+	}
+	return nil
+}
+
+func (f *filterFS) ignored(name string, isDir bool) (bool, error) {
+	if name == "." {
+		// The root of a Sub file system was checked by Sub.
+		return false, nil
+	}
+	res, err := f.pi.MatchEntry(context.Background(), path.Join(f.dir, name), isDir)
+	if err != nil {
+		return false, err
+	}
+	return res.Ok(), nil
+}
+
+// filterDir is an open directory of a filterFS, listing only entries that are not
+// ignored.
+type filterDir struct {
+	fs.ReadDirFile
+	fsys *filterFS
+	name string
+}
+
+func (d *filterDir) ReadDir(n int) ([]fs.DirEntry, error) {
+	if n <= 0 {
+		entries, err := d.ReadDirFile.ReadDir(n)
+		entries, ferr := d.fsys.filter(d.name, entries)
+		if ferr != nil {
+			return nil, ferr
+		}
+		return entries, err
+	}
+
+	// Keep reading while entries are filtered out, an empty result is only valid
+	// at the end of the directory.
+	var res []fs.DirEntry
+	for len(res) < n {
+		entries, err := d.ReadDirFile.ReadDir(n - len(res))
+		entries, ferr := d.fsys.filter(d.name, entries)
+		if ferr != nil {
+			return res, ferr
+		}
+		res = append(res, entries...)
+		if errors.Is(err, io.EOF) && len(res) > 0 {
+			return res, nil
+		} else if err != nil {
+			return res, err
+		}
+	}
+	return res, nil
+}
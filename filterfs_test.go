@@ -0,0 +1,73 @@
+package gopathignore_test
+
+import (
+	"io/fs"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/require"
+	gopathignore "github.com/vbhat161/go-path-ignore"
+	"github.com/vbhat161/go-path-ignore/match/gitignore"
+	"github.com/vbhat161/go-path-ignore/match/glob"
+)
+
+func TestFilterFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"main.go":             {Data: []byte("package main")},
+		"debug.log":           {},
+		"build/out.o":         {},
+		"build/sub/keep.log":  {},
+		"src/app.go":          {},
+		"src/app.tmp":         {},
+		"src/keep.log":        {},
+		"src/vendor/lib/x.go": {},
+		"docs/build/index.md": {},
+		"docs/readme.md":      {},
+		"tmp/a.tmp":           {},
+		"tmp/b.tmp":           {},
+	}
+
+	pi, err := gopathignore.New(gopathignore.Options{
+		GitIgnore: &gitignore.Options{Patterns: []string{"*.log", "!keep.log", "/build/", "vendor/"}},
+		Glob:      &glob.Options{Patterns: []string{"*.tmp"}},
+	})
+	require.NoError(t, err)
+
+	filtered := gopathignore.FilterFS(fsys, pi)
+	require.NoError(t, fstest.TestFS(filtered,
+		"main.go",
+		"src/app.go",
+		"src/keep.log",
+		"docs/build/index.md",
+		"docs/readme.md",
+		"tmp",
+	))
+
+	for _, name := range []string{"debug.log", "build", "build/out.o", "build/sub/keep.log", "src/vendor/lib/x.go", "tmp/a.tmp"} {
+		_, err := filtered.Open(name)
+		require.ErrorIs(t, err, fs.ErrNotExist, name)
+		_, err = fs.Stat(filtered, name)
+		require.ErrorIs(t, err, fs.ErrNotExist, name)
+	}
+
+	data, err := fs.ReadFile(filtered, "main.go")
+	require.NoError(t, err)
+	require.Equal(t, "package main", string(data))
+
+	entries, err := fs.ReadDir(filtered, "tmp")
+	require.NoError(t, err)
+	require.Empty(t, entries)
+
+	matches, err := fs.Glob(filtered, "src/*")
+	require.NoError(t, err)
+	require.Equal(t, []string{"src/app.go", "src/keep.log"}, matches)
+
+	// Paths in a sub tree are still matched relative to the root, "/build/" only
+	// applies to the top-level directory.
+	docs, err := fs.Sub(filtered, "docs")
+	require.NoError(t, err)
+	require.NoError(t, fstest.TestFS(docs, "build/index.md", "readme.md"))
+
+	_, err = fs.Sub(filtered, "src/vendor")
+	require.ErrorIs(t, err, fs.ErrNotExist)
+}
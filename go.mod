@@ -3,6 +3,7 @@ module github.com/vbhat161/go-path-ignore
 go 1.25.4
 
 require (
+	github.com/BurntSushi/toml v1.5.0
 	github.com/gobwas/glob v0.2.3
 	github.com/stretchr/testify v1.11.1
 )
@@ -19,5 +20,5 @@ require (
 	github.com/pmezard/go-difflib v1.0.1-0.20181226105442-5d4384ee4fb2 // indirect
 	github.com/wasilibs/go-re2 v1.10.0
 	go.uber.org/goleak v1.3.0
-	gopkg.in/yaml.v3 v3.0.1 // indirect
+	gopkg.in/yaml.v3 v3.0.1
 )
@@ -0,0 +1,270 @@
+package lint
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/vbhat161/go-path-ignore/match"
+	"github.com/vbhat161/go-path-ignore/match/gitignore"
+	"github.com/vbhat161/go-path-ignore/match/wildmatch"
+)
+
+// GitIgnore analyzes gitignore rules, loaded from opts as gitignore.NewMatcher
+// does. Rules are compared as git reads them, across all files in order of
+// precedence.
+func GitIgnore(opts gitignore.Options) ([]Finding, error) {
+	m, err := gitignore.NewMatcher(opts)
+	if err != nil {
+		return nil, err
+	}
+	return lintGitIgnore(m.State()), nil
+}
+
+// giRule is a gitignore rule as git reads it.
+type giRule struct {
+	src   match.Source
+	base  string
+	layer int
+	line  gitignore.Line
+	// pat matches the paths the rule matches, and below the paths inside the
+	// directories it matches. ok is false for malformed patterns.
+	pat, below pattern
+	ok         bool
+}
+
+func lintGitIgnore(s gitignore.State) []Finding {
+	flags := wildmatch.Pathname
+	if s.IgnoreCase {
+		flags |= wildmatch.CaseFold
+	}
+	inClass := func(class string, c byte) bool {
+		return wildmatch.Match(class, string([]byte{c}), flags)
+	}
+
+	var rules []*giRule
+	for li, layer := range s.Layers {
+		for _, rs := range layer.Rules {
+			r := &giRule{
+				src:   match.Source{Pattern: rs.Source, File: rs.File, Line: rs.Line, Negate: rs.Negate},
+				base:  layer.Base,
+				layer: li,
+			}
+			r.line, _ = gitignore.ParseLine(rs.Source)
+
+			toks, ok := wildmatchTokens(r.line.Pattern)
+			r.ok = ok
+			full := literal(layer.Base)
+			if r.line.Basename {
+				full = append(full, token{kind: tokDirs})
+			}
+			full = append(full, toks...)
+			r.pat = pattern{toks: full, pathname: true, fold: s.IgnoreCase, inClass: inClass}
+			r.below = r.pat
+			r.below.toks = append(append(full[:len(full):len(full)], token{kind: tokLit, c: '/'}), token{kind: tokAll})
+			rules = append(rules, r)
+		}
+	}
+
+	l := giLinter{rules: rules, engine: s.Engine}
+	var findings []Finding
+	for i := range rules {
+		findings = append(findings, l.lint(i)...)
+	}
+	return findings
+}
+
+func (r *giRule) key() string {
+	text := r.line.Pattern
+	if r.pat.fold {
+		text = strings.ToLower(text)
+	}
+	return fmt.Sprint(r.src.Negate, r.line.DirOnly, r.line.Basename, text)
+}
+
+// coversDirectly reports whether r matches every path o does.
+func (r *giRule) coversDirectly(o *giRule) bool {
+	return o.ok && (!r.line.DirOnly || o.line.DirOnly) && r.pat.covers(o.pat)
+}
+
+type giLinter struct {
+	rules  []*giRule
+	engine gitignore.Engine
+}
+
+func (l *giLinter) lint(i int) []Finding {
+	r := l.rules[i]
+	findings := l.compat(r)
+	if !r.ok {
+		return append(findings, l.finding(r, Error, CheckInvalid, "malformed pattern, git never matches it"))
+	}
+	if f, ok := l.duplicate(i); ok {
+		return append(findings, f)
+	}
+	if r.src.Negate {
+		if f, ok := l.negation(i); ok {
+			return append(findings, f)
+		}
+	}
+	if f, ok := l.shadowed(i); ok {
+		findings = append(findings, f)
+	}
+	return findings
+}
+
+func (l *giLinter) finding(r *giRule, sev Severity, check Check, format string, args ...any) Finding {
+	return newFinding(match.GitIgnore, r.src, sev, check, format, args...)
+}
+
+func (l *giLinter) ref(r *giRule) string {
+	return ref(match.GitIgnore, r.src)
+}
+
+// compat reports lines that git reads differently than the regex engine does,
+// or differently than they look.
+func (l *giLinter) compat(r *giRule) []Finding {
+	var findings []Finding
+	line := strings.TrimRight(r.src.Pattern, "\r")
+	trimmed := r.line.Text
+
+	if l.engine == gitignore.EngineRegex && strings.HasPrefix(line, " ") {
+		findings = append(findings, l.finding(r, Warning, CheckGitCompat,
+			"leading spaces are part of the pattern for git but trimmed by the regex engine, use the wildmatch engine"))
+	}
+	if l.engine == gitignore.EngineRegex && strings.HasSuffix(trimmed, `\ `) {
+		findings = append(findings, l.finding(r, Warning, CheckGitCompat,
+			"escaped trailing spaces are kept by git but not by the regex engine, use the wildmatch engine"))
+	}
+	if trimmed != line {
+		findings = append(findings, l.finding(r, Info, CheckGitCompat,
+			"trailing spaces are ignored, escape them with a backslash to match them"))
+	}
+	// A '#' after unescaped blanks looks like a trailing comment.
+	blank := false
+	for i := 0; i < len(trimmed); i++ {
+		switch trimmed[i] {
+		case '\\':
+			i++
+			blank = false
+		case ' ', '\t':
+			blank = true
+		case '#':
+			if blank {
+				findings = append(findings, l.finding(r, Warning, CheckGitCompat,
+					"'#' only starts a comment at the beginning of a line, git matches it literally"))
+				return findings
+			}
+		default:
+			blank = false
+		}
+	}
+	return findings
+}
+
+// duplicate reports a rule repeating an earlier one of the same file, unless a
+// rule of the opposite kind between them makes the repetition meaningful.
+func (l *giLinter) duplicate(i int) (Finding, bool) {
+	r := l.rules[i]
+	for j := i - 1; j >= 0 && l.rules[j].layer == r.layer; j-- {
+		o := l.rules[j]
+		if o.src.Negate != r.src.Negate {
+			break
+		}
+		if o.ok && o.key() == r.key() {
+			return l.finding(r, Warning, CheckDuplicate, "duplicate of %s", l.ref(o)), true
+		}
+	}
+	return Finding{}, false
+}
+
+// negation reports negated rules that cannot re-include anything, because no
+// earlier rule excludes paths they could apply to or because their paths are
+// inside directories that stay excluded.
+func (l *giLinter) negation(i int) (Finding, bool) {
+	r := l.rules[i]
+	orphan := true
+	for j := 0; j < i; j++ {
+		o := l.rules[j]
+		if !o.src.Negate && (strings.HasPrefix(r.base, o.base) || strings.HasPrefix(o.base, r.base)) {
+			orphan = false
+			break
+		}
+	}
+	if orphan {
+		return l.finding(r, Warning, CheckNegation, "negation without an earlier rule to override"), true
+	}
+
+	for j, o := range l.rules {
+		if j != i && l.excludesParents(j, i) {
+			return l.finding(r, Error, CheckNegation,
+				"cannot re-include a path inside a directory excluded by %s", l.ref(o)), true
+		}
+	}
+	return Finding{}, false
+}
+
+// shadowed reports rules that never decide, as a later rule matches every path
+// they do, and rules that repeat the decision of an earlier, broader one.
+func (l *giLinter) shadowed(i int) (Finding, bool) {
+	r := l.rules[i]
+	for k := len(l.rules) - 1; k > i; k-- {
+		o := l.rules[k]
+		if o.coversDirectly(r) && !l.redundantLater(i, k) {
+			return l.finding(r, Warning, CheckShadowed, "never decides, %s matches every path it does", l.ref(o)), true
+		}
+		if l.excludesParents(k, i) {
+			return l.finding(r, Warning, CheckShadowed, "never decides, its paths are inside directories excluded by %s", l.ref(o)), true
+		}
+	}
+
+	for j := i - 1; j >= 0; j-- {
+		o := l.rules[j]
+		if o.src.Negate != r.src.Negate {
+			break
+		}
+		if o.coversDirectly(r) {
+			return l.finding(r, Warning, CheckShadowed, "redundant, %s already matches every path it does", l.ref(o)), true
+		}
+	}
+	if !r.src.Negate {
+		for j := 0; j < i; j++ {
+			if l.excludesParents(j, i) {
+				return l.finding(r, Warning, CheckShadowed,
+					"redundant, its paths are inside directories excluded by %s", l.ref(l.rules[j])), true
+			}
+		}
+	}
+	return Finding{}, false
+}
+
+// redundantLater reports whether rule k is an equivalent of rule i reported as
+// redundant instead, as no rule of the opposite kind is between them.
+func (l *giLinter) redundantLater(i, k int) bool {
+	r, o := l.rules[i], l.rules[k]
+	if o.src.Negate != r.src.Negate || !r.coversDirectly(o) {
+		return false
+	}
+	for _, m := range l.rules[i+1 : k] {
+		if m.src.Negate != r.src.Negate {
+			return false
+		}
+	}
+	return true
+}
+
+// excludesParents reports whether rule j excludes a parent directory of every
+// path rule i matches, with no other negation that could re-include it.
+func (l *giLinter) excludesParents(j, i int) bool {
+	p, r := l.rules[j], l.rules[i]
+	if p.src.Negate || !p.ok || !p.below.covers(r.pat) {
+		return false
+	}
+	// Only negations that may match a directory p matches can re-include it.
+	for k := j + 1; k < len(l.rules); k++ {
+		o := l.rules[k]
+		if k == i || !o.src.Negate || !o.ok || p.below.covers(o.pat) || p.pat.disjoint(o.pat) {
+			continue
+		}
+		return false
+	}
+	return true
+}
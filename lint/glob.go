@@ -0,0 +1,77 @@
+package lint
+
+import (
+	"slices"
+	"strings"
+
+	gobwas "github.com/gobwas/glob"
+	"github.com/vbhat161/go-path-ignore/match"
+	"github.com/vbhat161/go-path-ignore/match/glob"
+)
+
+// Glob analyzes glob patterns. Any matching glob ignores a path, so a glob that
+// only matches paths another one does is redundant.
+func Glob(opts glob.Options) []Finding {
+	inClass := func(class string, c byte) bool {
+		g, err := gobwas.Compile(class)
+		return err == nil && g.Match(string([]byte{c}))
+	}
+
+	type globRule struct {
+		src match.Source
+		// key is the pattern as compiled.
+		key string
+		pat pattern
+		ok  bool
+	}
+	var rules []globRule
+	add := func(p string, line int, raw bool) {
+		r := globRule{src: match.Source{Pattern: p, Line: line}}
+		if opts.IgnoreCase {
+			p = strings.ToLower(p)
+		}
+		var toks []token
+		if raw {
+			toks, r.ok = literal(p), true
+			r.key = gobwas.QuoteMeta(p)
+		} else {
+			toks, r.ok = globTokens(p)
+			r.key = p
+		}
+		r.pat = pattern{toks: toks, fold: opts.IgnoreCase, inClass: inClass}
+		rules = append(rules, r)
+	}
+	for i, p := range opts.Patterns {
+		add(p, i+1, false)
+	}
+	for i, p := range opts.RawPatterns {
+		add(p, i+1, true)
+	}
+
+	var findings []Finding
+	for i, r := range rules {
+		if _, err := gobwas.Compile(r.key); err != nil {
+			findings = append(findings, newFinding(match.Glob, r.src, Error, CheckInvalid, "%v", err))
+			continue
+		}
+
+		dup := slices.IndexFunc(rules[:i], func(o globRule) bool { return o.key == r.key })
+		if dup >= 0 {
+			findings = append(findings, newFinding(match.Glob, r.src, Warning, CheckDuplicate, "duplicate of %s", ref(match.Glob, rules[dup].src)))
+			continue
+		}
+		if !r.ok {
+			continue
+		}
+		for j, o := range rules {
+			// Of two equivalent globs, the later one is redundant.
+			if j == i || !o.ok || !o.pat.covers(r.pat) || (j > i && r.pat.covers(o.pat)) {
+				continue
+			}
+			findings = append(findings, newFinding(match.Glob, r.src, Warning, CheckShadowed,
+				"redundant, %s matches every path it does", ref(match.Glob, o.src)))
+			break
+		}
+	}
+	return findings
+}
@@ -0,0 +1,132 @@
+// Package lint analyzes ignore rules for mistakes that make them behave other
+// than intended: duplicate and shadowed rules, negations that cannot take
+// effect, lines git reads differently than expected and regular expressions
+// that never match.
+package lint
+
+import (
+	"fmt"
+
+	gopathignore "github.com/vbhat161/go-path-ignore"
+	"github.com/vbhat161/go-path-ignore/match"
+)
+
+// Severity ranks findings.
+type Severity int
+
+const (
+	// Info marks rules that work but may be written other than intended.
+	Info Severity = iota
+	// Warning marks rules that have no effect or behave unexpectedly.
+	Warning
+	// Error marks rules that can never take effect.
+	Error
+)
+
+func (s Severity) String() string {
+	switch s {
+	case Info:
+		return "info"
+	case Warning:
+		return "warning"
+	case Error:
+		return "error"
+	default:
+		return fmt.Sprintf("severity(%d)", int(s))
+	}
+}
+
+func (s Severity) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+func (s *Severity) UnmarshalText(text []byte) error {
+	for _, v := range []Severity{Info, Warning, Error} {
+		if v.String() == string(text) {
+			*s = v
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown severity %q", text)
+}
+
+// Check names the analysis that produced a finding.
+type Check string
+
+const (
+	// CheckInvalid reports patterns that do not compile.
+	CheckInvalid Check = "invalid"
+	// CheckDuplicate reports patterns repeated without effect.
+	CheckDuplicate Check = "duplicate"
+	// CheckNegation reports negated gitignore patterns that cannot re-include
+	// anything.
+	CheckNegation Check = "negation"
+	// CheckShadowed reports rules that never change the result, because broader
+	// rules match every path they do.
+	CheckShadowed Check = "shadowed"
+	// CheckGitCompat reports gitignore lines that git reads differently than this
+	// library or than they appear to be meant.
+	CheckGitCompat Check = "git-compat"
+	// CheckUnmatchable reports regular expressions that match no relative path.
+	CheckUnmatchable Check = "unmatchable"
+)
+
+// Finding is a problem with a single rule.
+type Finding struct {
+	Severity Severity
+	Check    Check
+	Type     match.Type
+	// Source locates the rule, as reported by matches.
+	Source  match.Source
+	Message string
+}
+
+// Location returns file:line of the rule. Rules given in the options are named
+// after their strategy instead of a file.
+func (f Finding) Location() string {
+	file := f.Source.File
+	if file == "" {
+		file = f.Type.String()
+	}
+	return fmt.Sprintf("%s:%d", file, f.Source.Line)
+}
+
+func (f Finding) String() string {
+	return fmt.Sprintf("%s: %s: %s (%s)", f.Location(), f.Severity, f.Message, f.Check)
+}
+
+// Lint analyzes the rules of every strategy in opts, in the order PathIgnore
+// evaluates them. Options.IgnoreCase applies to all strategies, as for New.
+func Lint(opts gopathignore.Options) ([]Finding, error) {
+	var findings []Finding
+	if opts.Regex != nil {
+		regexOpts := *opts.Regex
+		regexOpts.IgnoreCase = regexOpts.IgnoreCase || opts.IgnoreCase
+		findings = append(findings, Regex(regexOpts)...)
+	}
+	if opts.GitIgnore != nil {
+		giOpts := *opts.GitIgnore
+		giOpts.IgnoreCase = giOpts.IgnoreCase || opts.IgnoreCase
+		res, err := GitIgnore(giOpts)
+		if err != nil {
+			return nil, fmt.Errorf("gitignore - %w", err)
+		}
+		findings = append(findings, res...)
+	}
+	if opts.Glob != nil {
+		globOpts := *opts.Glob
+		globOpts.IgnoreCase = globOpts.IgnoreCase || opts.IgnoreCase
+		findings = append(findings, Glob(globOpts)...)
+	}
+	return findings, nil
+}
+
+// newFinding returns a finding for the rule at src.
+func newFinding(typ match.Type, src match.Source, sev Severity, check Check, format string, args ...any) Finding {
+	return Finding{Severity: sev, Check: check, Type: typ, Source: src, Message: fmt.Sprintf(format, args...)}
+}
+
+// ref names another rule of the same strategy in a message.
+func ref(typ match.Type, src match.Source) string {
+	return Finding{Type: typ, Source: src}.Location()
+}
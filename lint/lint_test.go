@@ -0,0 +1,207 @@
+package lint_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	gopathignore "github.com/vbhat161/go-path-ignore"
+	"github.com/vbhat161/go-path-ignore/lint"
+	"github.com/vbhat161/go-path-ignore/match/gitignore"
+	"github.com/vbhat161/go-path-ignore/match/glob"
+	"github.com/vbhat161/go-path-ignore/match/regex"
+)
+
+// summary is the part of a finding the tests compare.
+type summary struct {
+	Location string
+	Severity lint.Severity
+	Check    lint.Check
+}
+
+func summarize(findings []lint.Finding) []summary {
+	var res []summary
+	for _, f := range findings {
+		res = append(res, summary{f.Location(), f.Severity, f.Check})
+	}
+	return res
+}
+
+func TestGitIgnore(t *testing.T) {
+	tests := []struct {
+		name     string
+		patterns []string
+		engine   gitignore.Engine
+		want     []summary
+	}{
+		{
+			name:     "clean",
+			patterns: []string{"*.log", "!keep.log", "build/*", "!build/keep", "/dist/", "# comment", ""},
+		},
+		{
+			name:     "duplicate",
+			patterns: []string{"*.log", "build/", "*.log"},
+			want:     []summary{{"gitignore:3", lint.Warning, lint.CheckDuplicate}},
+		},
+		{
+			name:     "repeated after negation",
+			patterns: []string{"a", "!a", "a", "*.log", "!x.log", "*.log"},
+			want: []summary{
+				{"gitignore:1", lint.Warning, lint.CheckShadowed},
+				{"gitignore:2", lint.Warning, lint.CheckShadowed},
+				{"gitignore:4", lint.Warning, lint.CheckShadowed},
+				{"gitignore:5", lint.Warning, lint.CheckShadowed},
+			},
+		},
+		{
+			name:     "orphan negation",
+			patterns: []string{"!keep.log", "*.tmp"},
+			want:     []summary{{"gitignore:1", lint.Warning, lint.CheckNegation}},
+		},
+		{
+			name:     "negation below excluded directory",
+			patterns: []string{"build/", "!build/keep", "logs", "!logs/**/x.log"},
+			want: []summary{
+				{"gitignore:2", lint.Error, lint.CheckNegation},
+				{"gitignore:4", lint.Error, lint.CheckNegation},
+			},
+		},
+		{
+			name:     "directory re-included",
+			patterns: []string{"build/", "!build/keep", "!build/"},
+			want:     []summary{{"gitignore:1", lint.Warning, lint.CheckShadowed}},
+		},
+		{
+			name:     "shadowed by later rule",
+			patterns: []string{"debug.log", "src/*.tmp", "*.log", "src/"},
+			want: []summary{
+				{"gitignore:1", lint.Warning, lint.CheckShadowed},
+				{"gitignore:2", lint.Warning, lint.CheckShadowed},
+			},
+		},
+		{
+			name:     "inside excluded directories",
+			patterns: []string{"a/*.log/x", "*.log", "[ab]", "a/*"},
+			want: []summary{
+				{"gitignore:1", lint.Warning, lint.CheckShadowed},
+				{"gitignore:4", lint.Warning, lint.CheckShadowed},
+			},
+		},
+		{
+			name:     "redundant after broader rule",
+			patterns: []string{"*.log", "logs/*.log", "node_modules/", "node_modules/**/*.js", "**/a?c", "abc"},
+			want: []summary{
+				{"gitignore:2", lint.Warning, lint.CheckShadowed},
+				{"gitignore:4", lint.Warning, lint.CheckShadowed},
+				{"gitignore:6", lint.Warning, lint.CheckShadowed},
+			},
+		},
+		{
+			name:     "not covered",
+			patterns: []string{"*.log", "*.log.bak", "build/", "src/build", "[ab]x", "cx", "a?", "abc", "/docs/*.md", "docs/api/*.md"},
+		},
+		{
+			name:     "git compat",
+			patterns: []string{" lead", `trail\ `, "space  ", "a.o # objects", `b\ #x`, "c#"},
+			want: []summary{
+				{"gitignore:1", lint.Warning, lint.CheckGitCompat},
+				{"gitignore:2", lint.Warning, lint.CheckGitCompat},
+				{"gitignore:3", lint.Info, lint.CheckGitCompat},
+				{"gitignore:4", lint.Warning, lint.CheckGitCompat},
+			},
+		},
+		{
+			name:     "git compat wildmatch",
+			patterns: []string{" lead", `trail\ `, "space  "},
+			engine:   gitignore.EngineWildmatch,
+			want:     []summary{{"gitignore:3", lint.Info, lint.CheckGitCompat}},
+		},
+		{
+			name:     "malformed",
+			patterns: []string{"[abc", `x\`},
+			engine:   gitignore.EngineWildmatch,
+			want: []summary{
+				{"gitignore:1", lint.Error, lint.CheckInvalid},
+				{"gitignore:2", lint.Error, lint.CheckInvalid},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			findings, err := lint.GitIgnore(gitignore.Options{Patterns: tt.patterns, Engine: tt.engine})
+			require.NoError(t, err)
+			require.Equal(t, tt.want, summarize(findings), findings)
+		})
+	}
+}
+
+func TestGitIgnoreTree(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "sub"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, ".gitignore"), []byte("*.log\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "sub", ".gitignore"), []byte("*.log\n!x\n"), 0o644))
+
+	findings, err := lint.GitIgnore(gitignore.Options{Root: root})
+	require.NoError(t, err)
+	sub := filepath.Join(root, "sub", ".gitignore")
+	require.Equal(t, []summary{{sub + ":1", lint.Warning, lint.CheckShadowed}}, summarize(findings), findings)
+	require.Equal(t, sub+":1: warning: redundant, "+filepath.Join(root, ".gitignore")+":1 already matches every path it does (shadowed)", findings[0].String())
+}
+
+func TestGlob(t *testing.T) {
+	findings := lint.Glob(glob.Options{
+		Patterns:    []string{"*.go", "main.go", "*.go", "[", "a?c", "abc", "x{a,b}", "xa"},
+		RawPatterns: []string{"lit[1].txt", "*.go"},
+	})
+	require.Equal(t, []summary{
+		{"glob:2", lint.Warning, lint.CheckShadowed},
+		{"glob:3", lint.Warning, lint.CheckDuplicate},
+		{"glob:4", lint.Error, lint.CheckInvalid},
+		{"glob:6", lint.Warning, lint.CheckShadowed},
+		{"glob:2", lint.Warning, lint.CheckShadowed},
+	}, summarize(findings), findings)
+}
+
+func TestRegex(t *testing.T) {
+	findings := lint.Regex(regex.Options{Patterns: []string{
+		`\.go$`, `^/etc/`, `^$`, `a^b`, `a$b`, `\.go$`, `(`, `^(/a|/b)`, `^/?a`, `(?m)^/a`, `(^/a|b)`,
+	}})
+	require.Equal(t, []summary{
+		{"regex:2", lint.Error, lint.CheckUnmatchable},
+		{"regex:3", lint.Error, lint.CheckUnmatchable},
+		{"regex:4", lint.Error, lint.CheckUnmatchable},
+		{"regex:5", lint.Error, lint.CheckUnmatchable},
+		{"regex:6", lint.Warning, lint.CheckDuplicate},
+		{"regex:7", lint.Error, lint.CheckInvalid},
+		{"regex:8", lint.Error, lint.CheckUnmatchable},
+	}, summarize(findings), findings)
+	require.Equal(t, "regex:2: error: never matches a relative path, paths are relative and do not start with a slash (unmatchable)", findings[0].String())
+}
+
+func TestLint(t *testing.T) {
+	findings, err := lint.Lint(gopathignore.Options{
+		Regex:     &regex.Options{Patterns: []string{"^/a"}},
+		GitIgnore: &gitignore.Options{Patterns: []string{"A", "a"}},
+		Glob:      &glob.Options{Patterns: []string{"X", "x"}},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []summary{
+		{"regex:1", lint.Error, lint.CheckUnmatchable},
+	}, summarize(findings))
+
+	findings, err = lint.Lint(gopathignore.Options{
+		GitIgnore:  &gitignore.Options{Patterns: []string{"A", "a"}},
+		Glob:       &glob.Options{Patterns: []string{"X", "x"}},
+		IgnoreCase: true,
+	})
+	require.NoError(t, err)
+	require.Equal(t, []summary{
+		{"gitignore:2", lint.Warning, lint.CheckDuplicate},
+		{"glob:2", lint.Warning, lint.CheckDuplicate},
+	}, summarize(findings))
+
+	_, err = lint.Lint(gopathignore.Options{GitIgnore: &gitignore.Options{FilePath: "missing"}})
+	require.Error(t, err)
+}
@@ -0,0 +1,276 @@
+package lint
+
+import "strings"
+
+// tokKind is the kind of a pattern token.
+type tokKind uint8
+
+const (
+	tokLit   tokKind = iota // a single byte
+	tokOne                  // ?, any single character
+	tokClass                // a bracket expression
+	tokStar                 // *, any run of characters other than a slash
+	tokDirs                 // **/, any number of leading directories
+	tokAll                  // any run of characters, slashes included
+)
+
+type token struct {
+	kind tokKind
+	c    byte
+	// class is the bracket expression of a tokClass, brackets included.
+	class string
+}
+
+// pattern is a tokenized gitignore or glob pattern, which covers can compare
+// with others.
+type pattern struct {
+	toks []token
+	// pathname is set if ?, * and bracket expressions do not match a slash.
+	pathname bool
+	fold     bool
+	// inClass reports whether a bracket expression matches c.
+	inClass func(class string, c byte) bool
+}
+
+func literal(s string) []token {
+	toks := make([]token, 0, len(s))
+	for i := 0; i < len(s); i++ {
+		toks = append(toks, token{kind: tokLit, c: s[i]})
+	}
+	return toks
+}
+
+// classEnd returns the index just past the bracket expression starting at
+// s[i], or -1 if it is not terminated.
+func classEnd(s string, i int) int {
+	j := i + 1
+	if j < len(s) && (s[j] == '!' || s[j] == '^') {
+		j++
+	}
+	// A closing bracket right after the opening one is literal.
+	if j < len(s) && s[j] == ']' {
+		j++
+	}
+	for ; j < len(s); j++ {
+		switch {
+		case s[j] == '\\':
+			j++
+		case s[j] == '[' && j+1 < len(s) && s[j+1] == ':':
+			if k := strings.Index(s[j+2:], ":]"); k >= 0 {
+				j += k + 3
+			}
+		case s[j] == ']':
+			return j + 1
+		}
+	}
+	return -1
+}
+
+// wildmatchTokens tokenizes a gitignore pattern as git's wildmatch reads it. It
+// returns false for malformed patterns, which match nothing.
+func wildmatchTokens(s string) ([]token, bool) {
+	var toks []token
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; c {
+		case '\\':
+			if i+1 == len(s) {
+				return nil, false
+			}
+			i++
+			toks = append(toks, token{kind: tokLit, c: s[i]})
+		case '?':
+			toks = append(toks, token{kind: tokOne})
+		case '[':
+			end := classEnd(s, i)
+			if end < 0 {
+				return nil, false
+			}
+			toks = append(toks, token{kind: tokClass, class: s[i:end]})
+			i = end - 1
+		case '*':
+			j := i
+			for j < len(s) && s[j] == '*' {
+				j++
+			}
+			// "**" is special only as a whole path component.
+			whole := j-i > 1 && (i == 0 || s[i-1] == '/') && (j == len(s) || s[j] == '/')
+			switch {
+			case whole && j == len(s):
+				toks = append(toks, token{kind: tokAll})
+			case whole:
+				toks = append(toks, token{kind: tokDirs})
+				j++
+			default:
+				toks = append(toks, token{kind: tokStar})
+			}
+			i = j - 1
+		default:
+			toks = append(toks, token{kind: tokLit, c: c})
+		}
+	}
+	return toks, true
+}
+
+// globTokens tokenizes a glob pattern. Without separators, * and ** both match
+// any run of characters. Patterns with alternatives are not supported.
+func globTokens(s string) ([]token, bool) {
+	var toks []token
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; c {
+		case '\\':
+			if i+1 == len(s) {
+				return nil, false
+			}
+			i++
+			toks = append(toks, token{kind: tokLit, c: s[i]})
+		case '?':
+			toks = append(toks, token{kind: tokOne})
+		case '[':
+			end := classEnd(s, i)
+			if end < 0 {
+				return nil, false
+			}
+			toks = append(toks, token{kind: tokClass, class: s[i:end]})
+			i = end - 1
+		case '*':
+			for i+1 < len(s) && s[i+1] == '*' {
+				i++
+			}
+			toks = append(toks, token{kind: tokAll})
+		case '{', '}':
+			return nil, false
+		default:
+			toks = append(toks, token{kind: tokLit, c: c})
+		}
+	}
+	return toks, true
+}
+
+// covers reports whether b matches every path r matches. It errs on the side of
+// false: patterns it cannot compare are not considered covered.
+func (b pattern) covers(r pattern) bool {
+	memo := make([]int8, (len(b.toks)+1)*(len(r.toks)+1))
+	var rec func(i, j int) bool
+	rec = func(i, j int) bool {
+		k := i*(len(r.toks)+1) + j
+		if memo[k] != 0 {
+			return memo[k] > 0
+		}
+		ok := b.cover(i, j, r, rec)
+		memo[k] = -1
+		if ok {
+			memo[k] = 1
+		}
+		return ok
+	}
+	return rec(0, 0)
+}
+
+// disjoint reports whether no path matches both a and b, as their literal
+// prefixes or suffixes differ. It errs on the side of false.
+func (a pattern) disjoint(b pattern) bool {
+	for i := 0; i < len(a.toks) && i < len(b.toks); i++ {
+		x, y := a.toks[i], b.toks[i]
+		if x.kind != tokLit || y.kind != tokLit {
+			break
+		}
+		if !a.sameByte(x.c, y.c) {
+			return true
+		}
+	}
+	for i, j := len(a.toks)-1, len(b.toks)-1; i >= 0 && j >= 0; i, j = i-1, j-1 {
+		x, y := a.toks[i], b.toks[j]
+		if x.kind != tokLit || y.kind != tokLit {
+			break
+		}
+		if !a.sameByte(x.c, y.c) {
+			return true
+		}
+	}
+	return false
+}
+
+// cover reports whether b.toks[i:] covers r.toks[j:], calling rec for the rest.
+func (b pattern) cover(i, j int, r pattern, rec func(i, j int) bool) bool {
+	if i == len(b.toks) {
+		return j == len(r.toks)
+	}
+
+	switch bt := b.toks[i]; bt.kind {
+	case tokAll:
+		for k := j; k <= len(r.toks); k++ {
+			if rec(i+1, k) {
+				return true
+			}
+		}
+		return false
+	case tokDirs:
+		// Nothing, or anything that ends with a slash. A "**/" of r matches
+		// nothing or a run ending with a slash, so it qualifies unless it
+		// follows other characters.
+		if rec(i+1, j) {
+			return true
+		}
+		for k := j; k < len(r.toks); k++ {
+			switch rt := r.toks[k]; {
+			case rt.kind == tokLit && rt.c == '/':
+			case rt.kind == tokDirs && (k == j || r.toks[k-1].kind == tokLit && r.toks[k-1].c == '/'):
+			default:
+				continue
+			}
+			if rec(i+1, k+1) {
+				return true
+			}
+		}
+		return false
+	case tokStar:
+		for k := j; ; k++ {
+			if rec(i+1, k) {
+				return true
+			}
+			if k == len(r.toks) || !r.singleSegment(r.toks[k]) {
+				return false
+			}
+		}
+	}
+
+	if j == len(r.toks) {
+		return false
+	}
+	rt := r.toks[j]
+	var ok bool
+	switch bt := b.toks[i]; bt.kind {
+	case tokLit:
+		ok = rt.kind == tokLit && b.sameByte(bt.c, rt.c)
+	case tokOne:
+		ok = (rt.kind == tokLit && (rt.c != '/' || !b.pathname)) || rt.kind == tokOne || rt.kind == tokClass
+	case tokClass:
+		ok = (rt.kind == tokLit && b.inClass(bt.class, rt.c)) || (rt.kind == tokClass && rt.class == bt.class)
+	}
+	return ok && rec(i+1, j+1)
+}
+
+// singleSegment reports whether t only matches characters other than a slash.
+func (r pattern) singleSegment(t token) bool {
+	switch t.kind {
+	case tokLit:
+		return t.c != '/'
+	case tokOne, tokClass, tokStar:
+		return r.pathname
+	}
+	return false
+}
+
+func (b pattern) sameByte(x, y byte) bool {
+	if b.fold {
+		return lower(x) == lower(y)
+	}
+	return x == y
+}
+
+func lower(c byte) byte {
+	if 'A' <= c && c <= 'Z' {
+		return c + 'a' - 'A'
+	}
+	return c
+}
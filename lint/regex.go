@@ -0,0 +1,213 @@
+package lint
+
+import (
+	"regexp/syntax"
+	"slices"
+
+	"github.com/vbhat161/go-path-ignore/match"
+	"github.com/vbhat161/go-path-ignore/match/regex"
+	regexp "github.com/wasilibs/go-re2"
+)
+
+// Regex analyzes regular expressions. As they are matched anywhere in a path,
+// only anchors can keep an expression from matching relative paths.
+func Regex(opts regex.Options) []Finding {
+	var findings []Finding
+	for i, p := range opts.Patterns {
+		src := match.Source{Pattern: p, Line: i + 1}
+		if j := slices.Index(opts.Patterns[:i], p); j >= 0 {
+			findings = append(findings, newFinding(match.Regex, src, Warning, CheckDuplicate,
+				"duplicate of %s", ref(match.Regex, match.Source{Line: j + 1})))
+			continue
+		}
+		if opts.Literals {
+			continue
+		}
+
+		expr := p
+		if opts.IgnoreCase {
+			expr = "(?i)" + expr
+		}
+		if _, err := regexp.Compile(expr); err != nil {
+			findings = append(findings, newFinding(match.Regex, src, Error, CheckInvalid, "%v", err))
+			continue
+		}
+		// RE2 syntax is a superset of Go's, expressions Go cannot parse are not
+		// analyzed further.
+		re, err := syntax.Parse(expr, syntax.Perl)
+		if err != nil {
+			continue
+		}
+		if reason := unmatchable(re.Simplify()); reason != "" {
+			findings = append(findings, newFinding(match.Regex, src, Error, CheckUnmatchable, "never matches a relative path, %s", reason))
+		}
+	}
+	return findings
+}
+
+// unmatchable returns why re matches no relative path, or "" if it may match
+// one.
+func unmatchable(re *syntax.Regexp) string {
+	for re.Op == syntax.OpCapture {
+		re = re.Sub[0]
+	}
+	alts := []*syntax.Regexp{re}
+	if re.Op == syntax.OpAlternate {
+		alts = re.Sub
+	}
+
+	var reason string
+	for _, alt := range alts {
+		if reason = unmatchableSeq(flatten(alt)); reason == "" {
+			return ""
+		}
+	}
+	return reason
+}
+
+// flatten returns the sequence of expressions re concatenates.
+func flatten(re *syntax.Regexp) []*syntax.Regexp {
+	switch re.Op {
+	case syntax.OpCapture:
+		return flatten(re.Sub[0])
+	case syntax.OpConcat:
+		var seq []*syntax.Regexp
+		for _, sub := range re.Sub {
+			seq = append(seq, flatten(sub)...)
+		}
+		return seq
+	}
+	return []*syntax.Regexp{re}
+}
+
+func unmatchableSeq(seq []*syntax.Regexp) string {
+	for i, re := range seq {
+		switch re.Op {
+		case syntax.OpBeginText:
+			for _, prev := range seq[:i] {
+				if minLen(prev) > 0 {
+					return "it requires characters before the start of the path"
+				}
+			}
+			for _, next := range seq[i+1:] {
+				if next.Op == syntax.OpEndText {
+					return "it only matches the empty path"
+				}
+				if maxLen(next) == 0 {
+					continue
+				}
+				if minLen(next) > 0 && startsWithSlash(next) {
+					return "paths are relative and do not start with a slash"
+				}
+				break
+			}
+		case syntax.OpEndText:
+			for _, next := range seq[i+1:] {
+				if minLen(next) > 0 {
+					return "it requires characters after the end of the path"
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// minLen returns the minimum number of characters re matches.
+func minLen(re *syntax.Regexp) int {
+	switch re.Op {
+	case syntax.OpLiteral:
+		return len(re.Rune)
+	case syntax.OpCharClass, syntax.OpAnyChar, syntax.OpAnyCharNotNL:
+		return 1
+	case syntax.OpCapture, syntax.OpPlus:
+		return minLen(re.Sub[0])
+	case syntax.OpRepeat:
+		return re.Min * minLen(re.Sub[0])
+	case syntax.OpConcat:
+		n := 0
+		for _, sub := range re.Sub {
+			n += minLen(sub)
+		}
+		return n
+	case syntax.OpAlternate:
+		n := -1
+		for _, sub := range re.Sub {
+			if m := minLen(sub); n < 0 || m < n {
+				n = m
+			}
+		}
+		return max(n, 0)
+	}
+	return 0
+}
+
+// maxLen returns the maximum number of characters re matches, -1 if unbounded.
+func maxLen(re *syntax.Regexp) int {
+	switch re.Op {
+	case syntax.OpLiteral:
+		return len(re.Rune)
+	case syntax.OpCharClass, syntax.OpAnyChar, syntax.OpAnyCharNotNL:
+		return 1
+	case syntax.OpCapture, syntax.OpQuest:
+		return maxLen(re.Sub[0])
+	case syntax.OpStar, syntax.OpPlus:
+		if maxLen(re.Sub[0]) == 0 {
+			return 0
+		}
+		return -1
+	case syntax.OpRepeat:
+		switch n := maxLen(re.Sub[0]); {
+		case n == 0:
+			return 0
+		case n < 0 || re.Max < 0:
+			return -1
+		default:
+			return re.Max * n
+		}
+	case syntax.OpConcat, syntax.OpAlternate:
+		n := 0
+		for _, sub := range re.Sub {
+			m := maxLen(sub)
+			if m < 0 {
+				return -1
+			}
+			if re.Op == syntax.OpConcat {
+				n += m
+			} else {
+				n = max(n, m)
+			}
+		}
+		return n
+	}
+	return 0
+}
+
+// startsWithSlash reports whether every non-empty match of re starts with a
+// slash.
+func startsWithSlash(re *syntax.Regexp) bool {
+	switch re.Op {
+	case syntax.OpLiteral:
+		return re.Rune[0] == '/'
+	case syntax.OpCharClass:
+		return len(re.Rune) == 2 && re.Rune[0] == '/' && re.Rune[1] == '/'
+	case syntax.OpCapture, syntax.OpPlus:
+		return startsWithSlash(re.Sub[0])
+	case syntax.OpRepeat:
+		return re.Min > 0 && startsWithSlash(re.Sub[0])
+	case syntax.OpConcat:
+		for _, sub := range re.Sub {
+			if maxLen(sub) == 0 {
+				continue
+			}
+			return minLen(sub) > 0 && startsWithSlash(sub)
+		}
+	case syntax.OpAlternate:
+		for _, sub := range re.Sub {
+			if minLen(sub) == 0 || !startsWithSlash(sub) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
@@ -0,0 +1,250 @@
+package gitignore
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// loadStandard adds the repository wide exclude sources of the work tree at root,
+// from the lowest to the highest precedence: the file named by core.excludesFile
+// (by default $XDG_CONFIG_HOME/git/ignore) and $GIT_DIR/info/exclude. Missing files
+// are skipped, as git does.
+func (gi *Matcher) loadStandard(root string, parallel bool) error {
+	configs, sources, err := standardSources(root)
+	if err != nil {
+		return err
+	}
+	gi.files = append(gi.files, configs...)
+
+	for _, src := range sources {
+		gi.files = append(gi.files, src)
+		patterns, err := readPath(src)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		} else if err != nil {
+			return err
+		}
+		rs, err := gi.newRuleSet("", []source{{path: src, lines: patterns}}, parallel)
+		if err != nil {
+			return fmt.Errorf("%s: %w", src, err)
+		}
+		gi.layers = append(gi.layers, rs)
+	}
+
+	return nil
+}
+
+// StandardFiles returns the git config and exclude files that ExcludeStandard
+// reads for the work tree at root, whether they exist or not. Which files these
+// are depends on the environment (HOME, XDG_CONFIG_HOME and GIT_CONFIG_*) and on
+// core.excludesFile.
+func StandardFiles(root string) ([]string, error) {
+	configs, sources, err := standardSources(root)
+	if err != nil {
+		return nil, err
+	}
+	return append(configs, sources...), nil
+}
+
+// standardSources returns the git config files looked at for the work tree at
+// root, and its exclude files from the lowest to the highest precedence.
+func standardSources(root string) (configs, sources []string, err error) {
+	gitDir, err := resolveGitDir(root)
+	if err != nil {
+		return nil, nil, err
+	}
+	excludesFile, configs, err := coreExcludesFile(root, gitDir)
+	if err != nil {
+		return nil, nil, err
+	}
+	if excludesFile != "" {
+		sources = append(sources, excludesFile)
+	}
+	if gitDir != "" {
+		sources = append(sources, filepath.Join(gitDir, "info", "exclude"))
+	}
+	return configs, sources, nil
+}
+
+// resolveGitDir returns the git directory of the work tree at root, following a
+// "gitdir:" file as used by worktrees and submodules. It returns an empty string
+// if root is not a git work tree.
+func resolveGitDir(root string) (string, error) {
+	dotGit := filepath.Join(root, ".git")
+	info, err := os.Stat(dotGit)
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", nil
+	} else if err != nil {
+		return "", err
+	}
+	if info.IsDir() {
+		return dotGit, nil
+	}
+
+	data, err := os.ReadFile(dotGit)
+	if err != nil {
+		return "", err
+	}
+	gitDir, ok := strings.CutPrefix(strings.TrimSpace(string(data)), "gitdir:")
+	if !ok {
+		return "", fmt.Errorf("invalid gitfile format: %s", dotGit)
+	}
+	gitDir = strings.TrimSpace(gitDir)
+	if !filepath.IsAbs(gitDir) {
+		gitDir = filepath.Join(root, gitDir)
+	}
+	return gitDir, nil
+}
+
+// coreExcludesFile returns the path configured by core.excludesFile, looking at the
+// system, global and repository config in git's order, or git's XDG default. It
+// also returns the config files it looked at.
+func coreExcludesFile(root, gitDir string) (excludesFile string, configs []string, err error) {
+	home, _ := os.UserHomeDir()
+	xdgHome := os.Getenv("XDG_CONFIG_HOME")
+	if xdgHome == "" && home != "" {
+		xdgHome = filepath.Join(home, ".config")
+	}
+
+	if os.Getenv("GIT_CONFIG_NOSYSTEM") == "" {
+		if p := os.Getenv("GIT_CONFIG_SYSTEM"); p != "" {
+			configs = append(configs, p)
+		} else {
+			configs = append(configs, "/etc/gitconfig")
+		}
+	}
+	if p := os.Getenv("GIT_CONFIG_GLOBAL"); p != "" {
+		configs = append(configs, p)
+	} else {
+		if xdgHome != "" {
+			configs = append(configs, filepath.Join(xdgHome, "git", "config"))
+		}
+		if home != "" {
+			configs = append(configs, filepath.Join(home, ".gitconfig"))
+		}
+	}
+	if gitDir != "" {
+		configs = append(configs, filepath.Join(gitDir, "config"))
+	}
+
+	for _, c := range configs {
+		v, ok, err := readConfigValue(c, "core", "excludesfile")
+		if err != nil {
+			return "", nil, fmt.Errorf("read git config %s: %w", c, err)
+		}
+		if ok {
+			excludesFile = v
+		}
+	}
+
+	switch {
+	case excludesFile == "":
+		if xdgHome == "" {
+			return "", configs, nil
+		}
+		return filepath.Join(xdgHome, "git", "ignore"), configs, nil
+	case excludesFile == "~" || strings.HasPrefix(excludesFile, "~/"):
+		return filepath.Join(home, excludesFile[1:]), configs, nil
+	case !filepath.IsAbs(excludesFile):
+		return filepath.Join(root, excludesFile), configs, nil
+	default:
+		return excludesFile, configs, nil
+	}
+}
+
+// readConfigValue returns the last value of section.key in the git config file at
+// path. Section and key names are case-insensitive; subsections are not supported.
+// A missing file is not an error.
+func readConfigValue(path, section, key string) (string, bool, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", false, nil
+	} else if err != nil {
+		return "", false, err
+	}
+	defer f.Close()
+
+	var (
+		value, current string
+		found          bool
+	)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == '#' || line[0] == ';' {
+			continue
+		}
+		if line[0] == '[' {
+			end := strings.IndexByte(line, ']')
+			if end < 0 {
+				return "", false, fmt.Errorf("invalid section header: %s", line)
+			}
+			current = strings.ToLower(strings.TrimSpace(line[1:end]))
+			line = strings.TrimSpace(line[end+1:])
+			if line == "" || line[0] == '#' || line[0] == ';' {
+				continue
+			}
+		}
+		if current != section {
+			continue
+		}
+
+		name, raw, hasValue := strings.Cut(line, "=")
+		if !strings.EqualFold(strings.TrimSpace(name), key) {
+			continue
+		}
+		if !hasValue {
+			// A key without a value is a boolean true, which is not a path.
+			value, found = "", false
+			continue
+		}
+		value, found = parseConfigValue(raw), true
+	}
+	return value, found, scanner.Err()
+}
+
+// parseConfigValue unquotes a git config value and drops trailing comments.
+func parseConfigValue(raw string) string {
+	var (
+		sb     strings.Builder
+		quoted bool
+		spaces int
+	)
+	raw = strings.TrimLeft(raw, " \t")
+	for i := 0; i < len(raw); i++ {
+		c := raw[i]
+		switch {
+		case c == '"':
+			quoted = !quoted
+			continue
+		case !quoted && (c == '#' || c == ';'):
+			i = len(raw)
+			continue
+		case c == '\\' && i+1 < len(raw):
+			i++
+			switch raw[i] {
+			case 'n':
+				c = '\n'
+			case 't':
+				c = '\t'
+			case 'b':
+				c = '\b'
+			default:
+				c = raw[i]
+			}
+		case !quoted && (c == ' ' || c == '\t'):
+			spaces++
+			continue
+		}
+		for ; spaces > 0; spaces-- {
+			sb.WriteByte(' ')
+		}
+		sb.WriteByte(c)
+	}
+	return sb.String()
+}
@@ -0,0 +1,111 @@
+package gitignore
+
+import (
+	"context"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExcludeStandard(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", "")
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+	t.Setenv("GIT_CONFIG_GLOBAL", "")
+	writeTree(t, home, map[string]string{
+		".config/git/ignore": "*.swp\n*.bak\n",
+		"my-ignore":          "*.tmp\n",
+	})
+
+	dir := t.TempDir()
+	gitAvailable := isGitAvailable(t)
+	if gitAvailable {
+		cmd := exec.Command("git", "init")
+		cmd.Dir = dir
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, "git init cmd run failure: %s", out)
+	}
+	writeTree(t, dir, map[string]string{
+		".git/info/exclude": "!keep.swp\nlocal.env\nsecret.txt\n",
+		".gitignore":        "!local.env\n!important.bak\n",
+	})
+
+	check := func(t *testing.T, matching, nonMatching []string) {
+		t.Helper()
+		for _, parallel := range []bool{false, true} {
+			gi, err := newMatcher(Options{Root: dir, ExcludeStandard: true}, parallel)
+			require.NoError(t, err)
+			for _, path := range matching {
+				if gitAvailable {
+					checkIgnoreOK, err := matchesGitCheckIgnore(t, path, dir)
+					require.NoError(t, err)
+					require.True(t, checkIgnoreOK, "wrong match expectation - path: %q", path)
+				}
+				matches, err := gi.Match(context.Background(), path)
+				require.NoError(t, err)
+				require.True(t, matches, "should match - path: %q, parallel: %v", path, parallel)
+			}
+			for _, path := range nonMatching {
+				if gitAvailable {
+					checkIgnoreOK, err := matchesGitCheckIgnore(t, path, dir)
+					require.NoError(t, err)
+					require.False(t, checkIgnoreOK, "wrong non-match expectation - path: %q", path)
+				}
+				matches, err := gi.Match(context.Background(), path)
+				require.NoError(t, err)
+				require.False(t, matches, "should not match - path: %q, parallel: %v", path, parallel)
+			}
+		}
+	}
+
+	t.Run("default excludes file", func(t *testing.T) {
+		check(t,
+			[]string{"a.swp", "src/b.bak", "secret.txt"},
+			[]string{"keep.swp", "local.env", "important.bak", "a.tmp"},
+		)
+	})
+
+	t.Run("configured excludes file", func(t *testing.T) {
+		writeTree(t, dir, map[string]string{
+			".git/config": "[core]\n\texcludesFile = \"~/my-ignore\" ; personal\n",
+		})
+		check(t,
+			[]string{"a.tmp", "secret.txt"},
+			[]string{"a.swp", "src/b.bak", "local.env"},
+		)
+	})
+
+	t.Run("requires root", func(t *testing.T) {
+		_, err := NewMatcher(Options{Patterns: []string{"*.log"}, ExcludeStandard: true})
+		require.Error(t, err)
+	})
+}
+
+func TestResolveGitDir(t *testing.T) {
+	dir := t.TempDir()
+	gitDir, err := resolveGitDir(dir)
+	require.NoError(t, err)
+	require.Empty(t, gitDir)
+
+	writeTree(t, dir, map[string]string{".git": "gitdir: ../main/.git/worktrees/wt\n"})
+	gitDir, err = resolveGitDir(dir)
+	require.NoError(t, err)
+	require.Equal(t, filepath.Join(dir, "../main/.git/worktrees/wt"), gitDir)
+}
+
+func TestParseConfigValue(t *testing.T) {
+	testCases := map[string]string{
+		` ~/.gitignore_global`:     `~/.gitignore_global`,
+		`/tmp/ignore   # comment`:  `/tmp/ignore`,
+		`"/path with/spaces" ; x`:  `/path with/spaces`,
+		`/path\\with\"escapes`:     `/path\with"escapes`,
+		`"quoted # not a comment"`: `quoted # not a comment`,
+		`a  b`:                     `a  b`,
+	}
+	for raw, want := range testCases {
+		require.Equal(t, want, parseConfigValue(raw), "raw: %q", raw)
+	}
+}
@@ -3,10 +3,15 @@ package gitignore
 import (
 	"context"
 	"fmt"
+	"io"
+	"io/fs"
 	"os"
+	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/vbhat161/go-path-ignore/match"
+	"github.com/vbhat161/go-path-ignore/match/wildmatch"
 	regexp "github.com/wasilibs/go-re2"
 )
 
@@ -28,25 +33,113 @@ var _ match.PathMatcher = (*Matcher)(nil) // enfore interface
 type rule struct {
 	re         *regexp.Regexp
 	src, rePat string
+	negate     bool
+	// dirOnly is set for patterns with a trailing slash, which only match directories.
+	dirOnly bool
+
+	// pattern is the wildmatch pattern, used instead of re by EngineWildmatch.
+	pattern string
+	// basename is set for wildmatch patterns without a slash, which match the
+	// last path component at any depth.
+	basename bool
+
+	// file and line locate src, file is empty for Options.Patterns.
+	file string
+	line int
 }
 
+func (r *rule) source() match.Source {
+	return match.Source{Pattern: r.src, File: r.file, Line: r.line, Negate: r.negate}
+}
+
+func (r *rule) matches(path string, flags wildmatch.Flags) bool {
+	if r.re != nil {
+		return r.re.MatchString(path)
+	}
+	if r.basename {
+		return wildmatch.Match(r.pattern, path[strings.LastIndexByte(path, '/')+1:], flags)
+	}
+	return wildmatch.Match(r.pattern, path, flags|wildmatch.Pathname)
+}
+
+// Engine selects how gitignore patterns are evaluated.
+type Engine int
+
+const (
+	// EngineRegex translates patterns to RE2 regular expressions. It is the default
+	// and the only engine that makes use of parallel (RE2 set) matching.
+	EngineRegex Engine = iota
+	// EngineWildmatch evaluates patterns with a port of git's wildmatch, following
+	// git exactly for POSIX character classes, bracket escapes and "**".
+	EngineWildmatch
+)
+
 // Matcher wraps a list of ignore pattern.
 type Matcher struct {
-	src []string
+	src        []string
+	engine     Engine
+	ignoreCase bool
+	parallel   bool
+
+	// layers are ordered from the lowest to the highest precedence.
+	layers []*ruleSet
+	// rules are the rules of all layers, in layer order, as numbered by Exprs.
+	rules []*rule
+	// files are the files and directories the layers were loaded from, see Files.
+	files []string
+}
+
+// ruleSet holds the rules of a single ignore source, e.g. one .gitignore file.
+type ruleSet struct {
+	// base is the slash terminated directory the patterns are relative to, empty
+	// for the root.
+	base       string
+	ignoreCase bool
 
-	posRules []*rule
-	negRules []*rule
+	// rules are kept in file order, the last matching rule decides.
+	rules []*rule
+	set   *match.RE2Set
+}
 
-	posSet, negSet *match.RE2Set
+// source is the content of an ignore file, or Options.Patterns if path is empty.
+type source struct {
+	path  string
+	lines []string
 }
 
 type Options struct {
 	Patterns []string
 	FilePath string
+	// Reader, if set, is read for patterns too. They rank above Patterns and
+	// FilePath.
+	Reader io.Reader
+	// ReaderName names Reader in match sources, e.g. with its file name or URL.
+	ReaderName string
+	// FS, if set, is the file system FilePath and Root are read from, instead of
+	// the OS file system. Paths are then slash separated, as for fs.FS, and Root
+	// may be "." for the root of FS. Not supported with ExcludeStandard.
+	FS fs.FS
+	// Root, if set, is searched recursively for .gitignore files. The patterns of
+	// each file only apply to paths below its directory, and deeper files take
+	// precedence over shallower ones (and over Patterns and FilePath). Matched
+	// paths are expected to be relative to Root.
+	Root string
+	// ExcludeStandard additionally applies git's repository wide exclude sources of
+	// the work tree at Root, like `git ls-files --exclude-standard`: the file named
+	// by core.excludesFile (default $XDG_CONFIG_HOME/git/ignore) and
+	// $GIT_DIR/info/exclude, in increasing order of precedence. Both rank below
+	// Patterns, FilePath and the .gitignore files. Requires Root.
+	ExcludeStandard bool
+	// Engine selects the pattern matching implementation, EngineRegex by default.
+	// Parallel matchers evaluate EngineWildmatch patterns sequentially.
+	Engine Engine
+	// IgnoreCase makes the patterns, and the directories of the ignore files they
+	// come from, match case-insensitively like git's core.ignoreCase.
+	IgnoreCase bool
 }
 
 // NewMatcher returns a new matcher for given patterns or from a file path. At least one
-// of patterns or filePath has to be present.
+// of patterns, filePath or root has to be present.
 func NewMatcher(opts Options) (*Matcher, error) {
 	return newMatcher(opts, false /*parallel*/)
 }
@@ -56,79 +149,162 @@ func NewParallelMatcher(opts Options) (*Matcher, error) {
 }
 
 func newMatcher(opts Options, parallel bool) (*Matcher, error) {
-	if len(opts.Patterns) == 0 && opts.FilePath == "" {
-		return nil, fmt.Errorf("atleast one gitignore source required: file or lines")
+	if len(opts.Patterns) == 0 && opts.FilePath == "" && opts.Reader == nil && opts.Root == "" {
+		return nil, fmt.Errorf("atleast one gitignore source required: file, lines, reader or root")
 	}
 
+	sources := []source{{lines: opts.Patterns}}
+	var files []string
 	if opts.FilePath != "" {
-		patterns, err := readPath(opts.FilePath)
+		var patterns []string
+		var err error
+		if opts.FS != nil {
+			patterns, err = readFS(opts.FS, opts.FilePath)
+		} else {
+			patterns, err = readPath(opts.FilePath)
+		}
 		if err != nil {
 			return nil, fmt.Errorf("read gitignore file: %w", err)
 		}
-		opts.Patterns = append(opts.Patterns, patterns...)
+		opts.Patterns = append(slices.Clip(opts.Patterns), patterns...)
+		sources = append(sources, source{path: opts.FilePath, lines: patterns})
+		files = append(files, opts.FilePath)
+	}
+	if opts.Reader != nil {
+		patterns, err := readLines(opts.Reader)
+		if err != nil {
+			return nil, fmt.Errorf("read gitignore reader: %w", err)
+		}
+		opts.Patterns = append(slices.Clip(opts.Patterns), patterns...)
+		sources = append(sources, source{path: opts.ReaderName, lines: patterns})
+	}
+
+	if opts.ExcludeStandard && opts.Root == "" {
+		return nil, fmt.Errorf("root required to exclude standard git sources")
+	}
+	if opts.ExcludeStandard && opts.FS != nil {
+		return nil, fmt.Errorf("standard git sources cannot be excluded from a fs.FS")
 	}
 
 	matcher := &Matcher{
-		src: opts.Patterns,
+		src:        opts.Patterns,
+		engine:     opts.Engine,
+		ignoreCase: opts.IgnoreCase,
+		parallel:   parallel && opts.Engine == EngineRegex,
+		files:      files,
+	}
+	if opts.ExcludeStandard {
+		if err := matcher.loadStandard(opts.Root, parallel); err != nil {
+			return nil, fmt.Errorf("load git exclude sources: %w", err)
+		}
 	}
-	for _, pattern := range opts.Patterns {
-		res, err := matcher.parse(pattern)
+
+	if len(opts.Patterns) > 0 {
+		rs, err := matcher.newRuleSet("", sources, parallel)
 		if err != nil {
-			return nil, fmt.Errorf("parse gitignore line(%s): %w", pattern, err)
+			return nil, err
 		}
-		if res == nil { // skip
-			continue
+		matcher.layers = append(matcher.layers, rs)
+	}
+
+	if opts.Root != "" {
+		fsys, root, name := opts.FS, opts.Root, func(name string) string { return name }
+		if fsys == nil {
+			fsys, root = os.DirFS(opts.Root), "."
+			name = func(name string) string { return filepath.Join(opts.Root, filepath.FromSlash(name)) }
+		}
+		if err := matcher.loadTree(fsys, root, name, parallel); err != nil {
+			return nil, fmt.Errorf("load gitignore tree: %w", err)
 		}
+	}
 
-		r := res.rule
-		if !parallel {
-			if re, err := regexp.Compile(r.rePat); err != nil {
-				return nil, fmt.Errorf("compile pattern %s - %w", pattern, err)
+	for _, rs := range matcher.layers {
+		matcher.rules = append(matcher.rules, rs.rules...)
+	}
+	return matcher, nil
+}
+
+func (gi *Matcher) newRuleSet(base string, sources []source, parallel bool) (*ruleSet, error) {
+	if gi.engine == EngineWildmatch {
+		parallel = false
+	}
+
+	rs := &ruleSet{base: base, ignoreCase: gi.ignoreCase}
+	for _, src := range sources {
+		for i, pattern := range src.lines {
+			var res *parseOut
+			var err error
+			if gi.engine == EngineWildmatch {
+				res = gi.parseWildmatch(pattern)
 			} else {
-				r.re = re
+				res, err = gi.parse(pattern)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("parse gitignore line(%s): %w", pattern, err)
+			}
+			if res == nil { // skip
+				continue
 			}
-		}
 
-		if res.negate {
-			matcher.negRules = append(matcher.negRules, res.rule)
-		} else {
-			matcher.posRules = append(matcher.posRules, res.rule)
-		}
-	}
+			r := res.rule
+			r.file, r.line = src.path, i+1
+			if gi.ignoreCase && gi.engine == EngineRegex {
+				r.rePat = "(?i)" + r.rePat
+			}
 
-	if parallel {
-		patterns := make([]string, 0, len(matcher.posRules))
-		for _, p := range matcher.posRules {
-			patterns = append(patterns, p.rePat)
+			rs.rules = append(rs.rules, r)
 		}
+	}
 
-		if set, err := match.NewRE2Set(patterns); err != nil {
-			return nil, fmt.Errorf("parallel: re2 set - %w", err)
-		} else {
-			matcher.posSet = set
+	if gi.engine == EngineRegex {
+		if err := rs.compile(parallel); err != nil {
+			return nil, err
 		}
+	}
+	return rs, nil
+}
 
-		if len(matcher.negRules) > 0 {
-			negPatterns := make([]string, 0, len(matcher.negRules))
-			for _, p := range matcher.negRules {
-				negPatterns = append(negPatterns, p.rePat)
-			}
-			if set, err := match.NewRE2Set(negPatterns); err != nil {
-				return nil, fmt.Errorf("parallel: negation re2 set - %w", err)
+// compile compiles the regular expressions of the rules, or a set of them in
+// parallel mode.
+func (rs *ruleSet) compile(parallel bool) error {
+	if !parallel {
+		for _, r := range rs.rules {
+			if re, err := regexp.Compile(r.rePat); err != nil {
+				return fmt.Errorf("compile pattern %s - %w", r.src, err)
 			} else {
-				matcher.negSet = set
+				r.re = re
 			}
 		}
-
+		return nil
 	}
 
-	return matcher, nil
+	if len(rs.rules) > 0 {
+		patterns := make([]string, 0, len(rs.rules))
+		for _, r := range rs.rules {
+			patterns = append(patterns, r.rePat)
+		}
+
+		if set, err := match.NewRE2Set(patterns); err != nil {
+			return fmt.Errorf("parallel: re2 set - %w", err)
+		} else {
+			rs.set = set
+		}
+	}
+	return nil
 }
 
 func (gi *Matcher) Type() match.Type {
 	return match.GitIgnore
 }
 
+// Files returns the files the rules were loaded from, and the directories searched
+// for ignore files under Options.Root, so that callers can detect changes. It also
+// lists the git config and standard exclude files looked at for ExcludeStandard,
+// whether they exist or not. Paths are those of FS if it was set.
+func (gi *Matcher) Files() []string {
+	return slices.Clone(gi.files)
+}
+
 // Matches takes a path and returns whether it is ignored according to the list of
 // ignore patterns. It returns true if the path should be ignored, and false otherwise.
 func (gi *Matcher) Match(ctx context.Context, path string) (bool, error) {
@@ -137,15 +313,18 @@ func (gi *Matcher) Match(ctx context.Context, path string) (bool, error) {
 }
 
 type result struct {
-	src string
+	rule *rule
 }
 
 func (r result) Ok() bool {
-	return r.src != ""
+	return r.rule != nil
 }
 
 func (r result) Src() string {
-	return r.src
+	if r.rule == nil {
+		return ""
+	}
+	return r.rule.src
 }
 
 func (r result) Type() match.Type {
@@ -153,59 +332,264 @@ func (r result) Type() match.Type {
 }
 
 func (r result) String() string {
-	return fmt.Sprintf("%s:%s", r.Type(), r.src)
+	return fmt.Sprintf("%s:%s", r.Type(), r.Src())
+}
+
+func (r result) Source() match.Source {
+	if r.rule == nil {
+		return match.Source{}
+	}
+	return r.rule.source()
 }
 
+// Match2 is like Match but also reports the pattern responsible for the match. A
+// trailing slash in path marks it as a directory, see MatchEntry.
 func (gi *Matcher) Match2(ctx context.Context, path string) (match.MatchInfo, error) {
 	// Replace OS-specific path separator.
 	path = strings.ReplaceAll(path, string(os.PathSeparator), "/")
+	return gi.MatchEntry(ctx, path, strings.HasSuffix(path, "/"))
+}
 
-	res := result{}
+// MatchEntry reports whether path is ignored, given whether it names a directory.
+// Patterns with a trailing slash (e.g. "build/") only match directories, so a
+// regular file called "build" is not ignored by them, while "build/out.o" is.
+func (gi *Matcher) MatchEntry(ctx context.Context, path string, isDir bool) (match.MatchInfo, error) {
+	// Replace OS-specific path separator.
+	path = strings.ReplaceAll(path, string(os.PathSeparator), "/")
+	return gi.match(ctx, strings.Trim(path, "/"), isDir)
+}
 
-	var matchPath string
-	if gi.posSet != nil {
+// Exprs returns the RE2 expressions of the rules of all sources, in increasing
+// order of precedence and in file order. The expressions of the rules of
+// .gitignore files below Options.Root only match paths below their directory.
+// Not supported by EngineWildmatch.
+func (gi *Matcher) Exprs() ([]string, error) {
+	if gi.engine == EngineWildmatch {
+		return nil, fmt.Errorf("wildmatch patterns cannot be lowered to RE2")
+	}
+
+	exprs := make([]string, 0, len(gi.rules))
+	for _, rs := range gi.layers {
+		for _, r := range rs.rules {
+			exprs = append(exprs, rs.expr(r))
+		}
+	}
+	return exprs, nil
+}
+
+// MatchSet is like MatchEntry, with the rules matching a path or one of its
+// parent directories given by hits.
+func (gi *Matcher) MatchSet(ctx context.Context, path string, isDir bool, hits func(string) []int) (match.MatchInfo, error) {
+	path = strings.ReplaceAll(path, string(os.PathSeparator), "/")
+	return gi.matchWith(ctx, strings.Trim(path, "/"), isDir, func(ctx context.Context, path string, isDir bool) (*rule, error) {
 		if ctx.Err() != nil {
-			return res, ctx.Err()
+			return nil, ctx.Err()
 		}
-		_, matchPath = gi.posSet.Matches(path)
-	} else {
-		for _, r := range gi.posRules {
-			if ctx.Err() != nil {
-				return res, ctx.Err()
+		// The rules are numbered from the lowest to the highest precedence source,
+		// so the last applicable one decides, like in decide.
+		idx := hits(path)
+		for i := len(idx) - 1; i >= 0; i-- {
+			if r := gi.rules[idx[i]]; isDir || !r.dirOnly {
+				return r, nil
+			}
+		}
+		return nil, nil
+	})
+}
+
+// match expects a slash separated path without leading or trailing slashes.
+func (gi *Matcher) match(ctx context.Context, path string, isDir bool) (match.MatchInfo, error) {
+	return gi.matchWith(ctx, path, isDir, gi.decide)
+}
+
+// matchWith is like match, with decide returning the rule deciding about a path.
+func (gi *Matcher) matchWith(
+	ctx context.Context,
+	path string,
+	isDir bool,
+	decide func(context.Context, string, bool) (*rule, error),
+) (match.MatchInfo, error) {
+	res := result{}
+
+	// Like git, which does not descend into excluded directories, a path below an
+	// excluded directory is excluded regardless of the rules for the path itself.
+	// A negated pattern cannot re-include it.
+	for i := 0; i < len(path); i++ {
+		if path[i] != '/' {
+			continue
+		}
+		if r, err := decide(ctx, path[:i], true /*isDir*/); err != nil {
+			return res, err
+		} else if r != nil && !r.negate {
+			res.rule = r
+			return res, nil
+		}
+	}
+
+	if r, err := decide(ctx, path, isDir); err != nil {
+		return res, err
+	} else if r != nil && !r.negate {
+		res.rule = r
+	}
+	return res, nil
+}
+
+// Explain returns every rule matching path or one of its parent directories, in
+// the order git evaluates them: parent directories first and, for each of them,
+// from the lowest to the highest precedence source in file order. The last rule
+// for a path decides, unless one of its parent directories is excluded.
+func (gi *Matcher) Explain(ctx context.Context, path string, isDir bool) ([]match.Hit, error) {
+	path = strings.ReplaceAll(path, string(os.PathSeparator), "/")
+	path = strings.Trim(path, "/")
+
+	var hits []match.Hit
+	// explain adds the hits for p and reports whether its decisive rule excludes it.
+	explain := func(p string, dir bool) (bool, error) {
+		hitPath := p
+		if dir {
+			hitPath += "/"
+		}
+		n := len(hits)
+		for _, rs := range gi.layers {
+			rules, err := rs.matchAll(ctx, p, dir)
+			if err != nil {
+				return false, err
 			}
-			if r.re.MatchString(path) {
-				matchPath = r.src
-				break
+			for _, r := range rules {
+				hits = append(hits, match.Hit{Type: match.GitIgnore, Source: r.source(), Path: hitPath})
 			}
 		}
+		if len(hits) == n {
+			return false, nil
+		}
+		return !hits[len(hits)-1].Negate, nil
 	}
 
-	if matchPath == "" {
-		return res, nil
+	for i := 0; i < len(path); i++ {
+		if path[i] != '/' {
+			continue
+		}
+		if excluded, err := explain(path[:i], true /*isDir*/); err != nil {
+			return nil, err
+		} else if excluded {
+			hits[len(hits)-1].Decisive = true
+			return hits, nil
+		}
+	}
+
+	n := len(hits)
+	if _, err := explain(path, isDir); err != nil {
+		return nil, err
+	}
+	if len(hits) > n {
+		hits[len(hits)-1].Decisive = true
 	}
+	return hits, nil
+}
+
+// decide returns the rule deciding about path, nil if there is none. The most
+// specific source that has an opinion about the path decides.
+func (gi *Matcher) decide(ctx context.Context, path string, isDir bool) (*rule, error) {
+	for i := len(gi.layers) - 1; i >= 0; i-- {
+		r, err := gi.layers[i].match(ctx, path, isDir)
+		if err != nil || r != nil {
+			return r, err
+		}
+	}
+	return nil, nil
+}
 
-	res.src = matchPath
+// match returns the last rule of the set matching path, so a negation only
+// re-includes paths excluded by earlier lines. It returns nil when no rule,
+// including negated ones, applies to path.
+func (rs *ruleSet) match(ctx context.Context, path string, isDir bool) (*rule, error) {
+	path, ok := rs.rel(path)
+	if !ok {
+		return nil, nil
+	}
 
-	if gi.negSet != nil {
+	if rs.set != nil {
 		if ctx.Err() != nil {
-			return res, ctx.Err()
-		}
-		if ok, path := gi.negSet.Matches(path); ok {
-			res.src = path
+			return nil, ctx.Err()
 		}
-		return res, nil
-	} else {
-		for _, r := range gi.negRules {
-			if ctx.Err() != nil {
-				return res, ctx.Err()
+		idx := rs.set.MatchIndices(path)
+		for i := len(idx) - 1; i >= 0; i-- {
+			if r := rs.rules[idx[i]]; isDir || !r.dirOnly {
+				return r, nil
 			}
-			if r.re.MatchString(path) {
-				res.src = ""
-				return res, nil
+		}
+		return nil, nil
+	}
+
+	for i := len(rs.rules) - 1; i >= 0; i-- {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if r := rs.rules[i]; (isDir || !r.dirOnly) && r.matches(path, rs.flags()) {
+			return r, nil
+		}
+	}
+	return nil, nil
+}
+
+// matchAll returns every rule of the set matching path, in file order.
+func (rs *ruleSet) matchAll(ctx context.Context, path string, isDir bool) ([]*rule, error) {
+	path, ok := rs.rel(path)
+	if !ok {
+		return nil, nil
+	}
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	var res []*rule
+	if rs.set != nil {
+		for _, i := range rs.set.MatchIndices(path) {
+			if r := rs.rules[i]; isDir || !r.dirOnly {
+				res = append(res, r)
 			}
 		}
 		return res, nil
 	}
+
+	for _, r := range rs.rules {
+		if (isDir || !r.dirOnly) && r.matches(path, rs.flags()) {
+			res = append(res, r)
+		}
+	}
+	return res, nil
+}
+
+// expr returns the expression of r matching whole paths: it is anchored at the
+// base directory of the set instead of the root.
+func (rs *ruleSet) expr(r *rule) string {
+	pat, flags := r.rePat, ""
+	if rest, ok := strings.CutPrefix(pat, "(?i)"); ok {
+		pat, flags = rest, "(?i)"
+	}
+	return flags + "^" + regexp.QuoteMeta(rs.base) + strings.TrimPrefix(pat, "^")
+}
+
+// rel returns path relative to the base directory of the set. ok is false if
+// path is not below it.
+func (rs *ruleSet) rel(path string) (rel string, ok bool) {
+	if rs.base == "" {
+		return path, true
+	}
+	if len(path) <= len(rs.base) {
+		return "", false
+	}
+	if prefix := path[:len(rs.base)]; prefix != rs.base && !(rs.ignoreCase && strings.EqualFold(prefix, rs.base)) {
+		return "", false
+	}
+	return path[len(rs.base):], true
+}
+
+func (rs *ruleSet) flags() wildmatch.Flags {
+	if rs.ignoreCase {
+		return wildmatch.CaseFold
+	}
+	return 0
 }
 
 // readPath uses an ignore file as the input, parses the lines out of
@@ -219,9 +603,114 @@ func readPath(gitignorePath string) ([]string, error) {
 	return strings.Split(string(data), "\n"), nil
 }
 
+// readFS is like readPath for a file of fsys.
+func readFS(fsys fs.FS, name string) ([]string, error) {
+	data, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return strings.Split(string(data), "\n"), nil
+}
+
+// readLines is like readPath for the content of r.
+func readLines(r io.Reader) ([]string, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return strings.Split(string(data), "\n"), nil
+}
+
 type parseOut struct {
-	rule   *rule
-	negate bool
+	rule *rule
+}
+
+// Line is a gitignore line as git reads it.
+type Line struct {
+	// Text is the line without carriage return and unescaped trailing spaces.
+	Text string
+	// Pattern is the wildmatch pattern of the line, without negation, leading
+	// and trailing slash.
+	Pattern string
+	Negate  bool
+	// DirOnly is set for patterns with a trailing slash, which only match
+	// directories.
+	DirOnly bool
+	// Basename is set for patterns without a slash, which match the last path
+	// component at any depth.
+	Basename bool
+}
+
+// ParseLine parses a gitignore line the way git does. It returns false for
+// blank lines, comments and lines without a pattern.
+func ParseLine(l string) (Line, bool) {
+	// Trim OS-specific carriage returns.
+	l = strings.TrimRight(l, "\r")
+	if strings.HasPrefix(l, "#") {
+		return Line{}, false
+	}
+
+	l = trimTrailingSpaces(l)
+	line := Line{Text: l}
+	if l == "" {
+		return line, false
+	}
+	if l[0] == '!' {
+		line.Negate = true
+		l = l[1:]
+	}
+
+	if strings.HasSuffix(l, "/") {
+		line.DirOnly = true
+		l = l[:len(l)-1]
+	}
+	// A leading slash anchors the pattern, like any other slash, but is not part
+	// of the match.
+	line.Basename = !strings.Contains(l, "/")
+	line.Pattern = strings.TrimPrefix(l, "/")
+	return line, line.Pattern != ""
+}
+
+// parseWildmatch parses a line the way git does for its wildmatch based matching.
+func (gi *Matcher) parseWildmatch(l string) *parseOut {
+	line, ok := ParseLine(l)
+	if !ok {
+		return nil
+	}
+	return &parseOut{rule: &rule{
+		src:      l,
+		pattern:  line.Pattern,
+		negate:   line.Negate,
+		dirOnly:  line.DirOnly,
+		basename: line.Basename,
+	}}
+}
+
+// trimTrailingSpaces removes trailing spaces unless they are escaped with a backslash.
+func trimTrailingSpaces(l string) string {
+	lastSpace := -1
+	for i := 0; i < len(l); i++ {
+		switch l[i] {
+		case ' ':
+			if lastSpace < 0 {
+				lastSpace = i
+			}
+		case '\\':
+			i++
+			if i == len(l) {
+				return l
+			}
+			lastSpace = -1
+		default:
+			lastSpace = -1
+		}
+	}
+	if lastSpace >= 0 {
+		return l[:lastSpace]
+	}
+	return l
 }
 
 // This code is an improvised version of github.com/sabhiram/go-gitignore
@@ -245,8 +734,14 @@ func (gi *Matcher) parse(l string) (*parseOut, error) {
 		return nil, nil
 	}
 
-	hasfwSlashSuffix := strings.HasSuffix(l, "/")
-	hasFwSlash := strings.Contains(l[:len(l)-1], "/") // except for terminal fw slash
+	// A trailing slash restricts the pattern to directories, it takes no part in
+	// the matching itself.
+	dirOnly := strings.HasSuffix(l, "/")
+	l = strings.TrimSuffix(l, "/")
+	if l == "" {
+		return nil, nil
+	}
+	hasFwSlash := strings.Contains(l, "/")
 
 	negate := false
 	if l[0] == '!' {
@@ -290,12 +785,7 @@ func (gi *Matcher) parse(l string) (*parseOut, error) {
 
 	l = strings.ReplaceAll(l, placeholder, "*")
 
-	expr := ""
-	if hasfwSlashSuffix {
-		expr = l + "(?:|.*)$"
-	} else {
-		expr = l + "(?:|/.*)$"
-	}
+	expr := l + "$"
 
 	if hasFwSlash {
 		if strings.HasPrefix(l, "/") {
@@ -306,6 +796,6 @@ func (gi *Matcher) parse(l string) (*parseOut, error) {
 		expr = "^(?:|.*/)" + expr
 	}
 
-	rule := &rule{src: input, rePat: expr}
-	return &parseOut{rule: rule, negate: negate}, nil
+	rule := &rule{src: input, rePat: expr, negate: negate, dirOnly: dirOnly}
+	return &parseOut{rule: rule}, nil
 }
@@ -7,18 +7,21 @@ import (
 	"os/exec"
 	"strings"
 	"testing"
+	"testing/iotest"
 
 	"github.com/stretchr/testify/require"
+	"github.com/vbhat161/go-path-ignore/match"
 )
 
 func TestGitIgnore(t *testing.T) {
 	gi, err := NewMatcher(Options{Patterns: []string{`file-?.*.log`, `!important.txt`}})
 	require.NoError(t, err)
 	require.NotEmpty(t, gi)
-	require.Len(t, gi.posRules, 1)
-	require.Len(t, gi.negRules, 1)
-	require.Equal(t, `^(?:|.*/)file-[^/]\.[^/]*\.log(?:|/.*)$`, gi.posRules[0].re.String())
-	require.Equal(t, `^(?:|.*/)important\.txt(?:|/.*)$`, gi.negRules[0].re.String())
+	require.Len(t, gi.layers[0].rules, 2)
+	require.False(t, gi.layers[0].rules[0].negate)
+	require.True(t, gi.layers[0].rules[1].negate)
+	require.Equal(t, `^(?:|.*/)file-[^/]\.[^/]*\.log$`, gi.layers[0].rules[0].re.String())
+	require.Equal(t, `^(?:|.*/)important\.txt$`, gi.layers[0].rules[1].re.String())
 }
 
 func TestGitIgnoreMatches(t *testing.T) {
@@ -856,10 +859,6 @@ func TestGitIgnoreMatches(t *testing.T) {
 				defer os.Remove(gitIgnorePath)
 				_, err = os.Stat(gitIgnorePath)
 				require.NoError(tt, err, ".gitignore is not created")
-				gi, err := NewMatcher(Options{FilePath: gitIgnorePath})
-				if err != nil {
-					tt.Fatalf("failed to create gitignore from pattern %q: %v", tc.pattern, err)
-				}
 				for _, path := range tc.matching {
 					if gitAvailable {
 						checkIgnoreOK, err := matchesGitCheckIgnore(tt, path, dir)
@@ -867,11 +866,6 @@ func TestGitIgnoreMatches(t *testing.T) {
 						require.True(tt, checkIgnoreOK,
 							"wrong match expectation - pattern: %q, path: %q", tc.pattern, path)
 					}
-					matches, err := gi.Match(context.Background(), path)
-					require.NoError(tt, err)
-
-					require.True(tt, matches,
-						"should match - pattern: %q, path: %q", tc.pattern, path)
 				}
 				for _, path := range tc.nonMatching {
 					if gitAvailable {
@@ -879,9 +873,25 @@ func TestGitIgnoreMatches(t *testing.T) {
 						require.NoError(tt, err, "git-check-ignore failure - pattern: %q, path: %q", tc.pattern, path)
 						require.False(tt, checkIgnoreOK, "wrong non-match expectation - pattern: %q, path: %q", tc.pattern, path)
 					}
-					matches, err := gi.Match(context.Background(), path)
-					require.NoError(tt, err)
-					require.False(tt, matches, "should not match - pattern: %q, path: %q", tc.pattern, path)
+				}
+
+				for _, engine := range []Engine{EngineRegex, EngineWildmatch} {
+					gi, err := NewMatcher(Options{FilePath: gitIgnorePath, Engine: engine})
+					if err != nil {
+						tt.Fatalf("failed to create gitignore from pattern %q: %v", tc.pattern, err)
+					}
+					for _, path := range tc.matching {
+						matches, err := gi.Match(context.Background(), path)
+						require.NoError(tt, err)
+						require.True(tt, matches,
+							"should match - pattern: %q, path: %q, engine: %d", tc.pattern, path, engine)
+					}
+					for _, path := range tc.nonMatching {
+						matches, err := gi.Match(context.Background(), path)
+						require.NoError(tt, err)
+						require.False(tt, matches,
+							"should not match - pattern: %q, path: %q, engine: %d", tc.pattern, path, engine)
+					}
 				}
 			})
 		}
@@ -929,6 +939,289 @@ func TestGitIgnoreMatches(t *testing.T) {
 	})
 }
 
+// TestGitParity checks gitignore semantics against git check-ignore.
+func TestGitParity(t *testing.T) {
+	dir := t.TempDir()
+	gitAvailable := isGitAvailable(t)
+	if gitAvailable {
+		cmd := exec.Command("git", "init")
+		cmd.Dir = dir
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, "git init cmd run failure: %s", out)
+	}
+
+	testCases := []struct {
+		name                  string
+		patterns              []string
+		matching, nonMatching []string
+		// engines are the engines to check, both by default.
+		engines []Engine
+	}{
+		{
+			name:        "negation before positive rule",
+			patterns:    []string{"!keep.log", "*.log"},
+			matching:    []string{"keep.log", "debug.log", "src/keep.log"},
+			nonMatching: []string{"keep.txt"},
+		},
+		{
+			name:        "negation after positive rule",
+			patterns:    []string{"*.log", "!keep.log"},
+			matching:    []string{"debug.log", "src/debug.log"},
+			nonMatching: []string{"keep.log", "src/keep.log"},
+		},
+		{
+			name:        "re-excluded after negation",
+			patterns:    []string{"*.log", "!keep*.log", "keep-not.log"},
+			matching:    []string{"debug.log", "keep-not.log"},
+			nonMatching: []string{"keep.log", "keep-1.log"},
+		},
+		{
+			name:        "only negations",
+			patterns:    []string{"!*.log"},
+			nonMatching: []string{"debug.log", "main.go"},
+		},
+		{
+			name:        "negation below excluded directory",
+			patterns:    []string{"logs/", "!logs/keep.txt"},
+			matching:    []string{"logs/keep.txt", "logs/debug.txt", "src/logs/keep.txt"},
+			nonMatching: []string{"keep.txt", "logs"},
+		},
+		{
+			name:        "negation below excluded directory contents",
+			patterns:    []string{"logs/*", "!logs/keep.txt"},
+			matching:    []string{"logs/debug.txt", "logs/sub/keep.txt"},
+			nonMatching: []string{"logs/keep.txt", "logs"},
+		},
+		{
+			name:        "re-included directory",
+			patterns:    []string{"/*", "!/src/", "/src/*.tmp"},
+			matching:    []string{"README.md", "docs/index.md", "src/a.tmp"},
+			nonMatching: []string{"src/", "src/main.go", "src/pkg/util.go"},
+		},
+		{
+			name:        "negated directory below excluded parent",
+			patterns:    []string{"build/", "!build/keep/"},
+			matching:    []string{"build/keep/", "build/keep/a.o"},
+			nonMatching: []string{"src/keep/a.o"},
+		},
+		{
+			name:        "double asterisk children exclude directory contents only",
+			patterns:    []string{"abc/**"},
+			matching:    []string{"abc/x", "abc/x/y", "abc/x/"},
+			nonMatching: []string{"abc"},
+		},
+		{
+			name:        "POSIX character class",
+			patterns:    []string{"[[:digit:]]*.log"},
+			matching:    []string{"1.log", "src/2a.log"},
+			nonMatching: []string{"a.log", "src/b1.log"},
+			engines:     []Engine{EngineWildmatch},
+		},
+		{
+			name:        "closing bracket as first class member",
+			patterns:    []string{"a[]]b"},
+			matching:    []string{"a]b", "src/a]b"},
+			nonMatching: []string{"ab", "a[b"},
+			engines:     []Engine{EngineWildmatch},
+		},
+		{
+			name:        "escape inside brackets",
+			patterns:    []string{`x[\]-]y`},
+			matching:    []string{"x]y", "x-y"},
+			nonMatching: []string{`x\y`, "xay"},
+			engines:     []Engine{EngineWildmatch},
+		},
+		{
+			name:        "escaped trailing space",
+			patterns:    []string{`foo\ `},
+			matching:    []string{"foo ", "src/foo "},
+			nonMatching: []string{"foo"},
+			engines:     []Engine{EngineWildmatch},
+		},
+		{
+			name:        "leading spaces are significant",
+			patterns:    []string{" bar"},
+			matching:    []string{" bar"},
+			nonMatching: []string{"bar"},
+			engines:     []Engine{EngineWildmatch},
+		},
+		{
+			name:        "double asterisks with character class",
+			patterns:    []string{"**/logs/**/*.[ch]"},
+			matching:    []string{"logs/a.c", "x/logs/y/z/b.h"},
+			nonMatching: []string{"logs.c", "x/logs/a.o"},
+			engines:     []Engine{EngineWildmatch},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			engines := tc.engines
+			if engines == nil {
+				engines = []Engine{EngineRegex, EngineWildmatch}
+			}
+			requireGitParity(t, dir, gitAvailable, Options{Patterns: tc.patterns}, tc.matching, tc.nonMatching, engines...)
+		})
+	}
+}
+
+func TestIgnoreCase(t *testing.T) {
+	dir := t.TempDir()
+	gitAvailable := isGitAvailable(t)
+	if gitAvailable {
+		for _, args := range [][]string{{"init"}, {"config", "core.ignoreCase", "true"}} {
+			cmd := exec.Command("git", args...)
+			cmd.Dir = dir
+			out, err := cmd.CombinedOutput()
+			require.NoError(t, err, "git %v cmd run failure: %s", args, out)
+		}
+	}
+
+	testCases := []struct {
+		name                  string
+		patterns              []string
+		matching, nonMatching []string
+	}{
+		{
+			name:        "literal",
+			patterns:    []string{"*.LOG", "/Build/"},
+			matching:    []string{"debug.log", "src/Debug.Log", "build/out.o", "BUILD/out.o"},
+			nonMatching: []string{"debug.txt", "src/build/out.o"},
+		},
+		{
+			name:        "negation",
+			patterns:    []string{"*.log", "!KEEP.log"},
+			matching:    []string{"debug.LOG"},
+			nonMatching: []string{"keep.log", "Keep.Log"},
+		},
+		{
+			name:        "character class",
+			patterns:    []string{"[a-c]*.txt"},
+			matching:    []string{"Alpha.txt", "beta.TXT"},
+			nonMatching: []string{"Delta.txt"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			requireGitParity(t, dir, gitAvailable, Options{Patterns: tc.patterns, IgnoreCase: true},
+				tc.matching, tc.nonMatching, EngineRegex, EngineWildmatch)
+		})
+	}
+
+	t.Run("nested gitignore directory", func(t *testing.T) {
+		root := t.TempDir()
+		writeTree(t, root, map[string]string{"Sub/.gitignore": "*.tmp\n"})
+		gi, err := NewMatcher(Options{Root: root, IgnoreCase: true})
+		require.NoError(t, err)
+		ok, err := gi.Match(context.Background(), "sub/a.TMP")
+		require.NoError(t, err)
+		require.True(t, ok)
+	})
+}
+
+// requireGitParity checks the expectations for the patterns against both the
+// sequential and parallel matchers of the engines and, if available, against git
+// itself.
+func requireGitParity(
+	t *testing.T, dir string, gitAvailable bool, opts Options, matching, nonMatching []string, engines ...Engine,
+) {
+	t.Helper()
+	if gitAvailable {
+		gitIgnorePath := dir + "/.gitignore"
+		require.NoError(t, os.WriteFile(gitIgnorePath, []byte(strings.Join(opts.Patterns, "\n")+"\n"), 0o600))
+		defer os.Remove(gitIgnorePath)
+	}
+
+	for _, path := range matching {
+		if gitAvailable {
+			checkIgnoreOK, err := matchesGitCheckIgnore(t, path, dir)
+			require.NoError(t, err)
+			require.True(t, checkIgnoreOK, "wrong match expectation - path: %q", path)
+		}
+	}
+	for _, path := range nonMatching {
+		if gitAvailable {
+			checkIgnoreOK, err := matchesGitCheckIgnore(t, path, dir)
+			require.NoError(t, err)
+			require.False(t, checkIgnoreOK, "wrong non-match expectation - path: %q", path)
+		}
+	}
+
+	for _, engine := range engines {
+		for _, parallel := range []bool{false, true} {
+			opts.Engine = engine
+			gi, err := newMatcher(opts, parallel)
+			require.NoError(t, err)
+			for _, path := range matching {
+				matches, err := gi.Match(context.Background(), path)
+				require.NoError(t, err)
+				require.True(t, matches,
+					"should match - path: %q, engine: %d, parallel: %v", path, engine, parallel)
+			}
+			for _, path := range nonMatching {
+				matches, err := gi.Match(context.Background(), path)
+				require.NoError(t, err)
+				require.False(t, matches,
+					"should not match - path: %q, engine: %d, parallel: %v", path, engine, parallel)
+			}
+		}
+	}
+}
+
+func TestMatchEntry(t *testing.T) {
+	gi, err := NewMatcher(Options{Patterns: []string{"build/", "/bin/", "*.log", "abc/**"}})
+	require.NoError(t, err)
+
+	testCases := []struct {
+		path  string
+		isDir bool
+		want  bool
+	}{
+		{path: "build", isDir: true, want: true},
+		{path: "build", isDir: false, want: false},
+		{path: "src/build", isDir: true, want: true},
+		{path: "src/build", isDir: false, want: false},
+		{path: "build/out.o", isDir: false, want: true},
+		{path: "src/build/out.o", isDir: false, want: true},
+		{path: "build/", isDir: false, want: false},
+		{path: "bin", isDir: true, want: true},
+		{path: "bin", isDir: false, want: false},
+		{path: "src/bin", isDir: true, want: false},
+		{path: "debug.log", isDir: false, want: true},
+		{path: "debug.log", isDir: true, want: true},
+		{path: "abc", isDir: true, want: false},
+		{path: "abc/x", isDir: true, want: true},
+	}
+	for _, tc := range testCases {
+		res, err := gi.MatchEntry(context.Background(), tc.path, tc.isDir)
+		require.NoError(t, err)
+		require.Equal(t, tc.want, res.Ok(), "path: %q, isDir: %v", tc.path, tc.isDir)
+	}
+}
+
+func TestParseLine(t *testing.T) {
+	testCases := []struct {
+		line string
+		want Line
+		ok   bool
+	}{
+		{line: "*.log", want: Line{Text: "*.log", Pattern: "*.log", Basename: true}, ok: true},
+		{line: "!/build/\r", want: Line{Text: "!/build/", Pattern: "build", Negate: true, DirOnly: true}, ok: true},
+		{line: "docs/*.md  ", want: Line{Text: "docs/*.md", Pattern: "docs/*.md"}, ok: true},
+		{line: `foo\ `, want: Line{Text: `foo\ `, Pattern: `foo\ `, Basename: true}, ok: true},
+		{line: "# comment"},
+		{line: "   ", want: Line{}},
+		{line: "/", want: Line{Text: "/", DirOnly: true, Basename: true}},
+	}
+
+	for _, tc := range testCases {
+		got, ok := ParseLine(tc.line)
+		require.Equal(t, tc.ok, ok, "%q", tc.line)
+		require.Equal(t, tc.want, got, "%q", tc.line)
+	}
+}
+
 func BenchmarkGitIgnoreMatches(b *testing.B) {
 	patterns := []string{
 		"*.log",
@@ -1354,3 +1647,61 @@ func matchesGitCheckIgnore(t *testing.T, path string, dir string) (bool, error)
 	cmdOut := strings.Trim(string(out), "\n")
 	return cmdOut == path, cmdErr
 }
+
+func TestExplain(t *testing.T) {
+	patterns := []string{"*.log", "!keep.log", "logs/", "!logs/keep.log"}
+	for _, engine := range []Engine{EngineRegex, EngineWildmatch} {
+		for _, parallel := range []bool{false, true} {
+			opts := Options{Patterns: patterns, Engine: engine}
+			var m *Matcher
+			var err error
+			if parallel {
+				m, err = NewParallelMatcher(opts)
+			} else {
+				m, err = NewMatcher(opts)
+			}
+			require.NoError(t, err)
+
+			hits, err := m.Explain(context.Background(), "keep.log", false)
+			require.NoError(t, err)
+			require.Equal(t, []match.Hit{
+				{Type: match.GitIgnore, Source: match.Source{Pattern: "*.log", Line: 1}, Path: "keep.log"},
+				{Type: match.GitIgnore, Source: match.Source{Pattern: "!keep.log", Line: 2, Negate: true}, Path: "keep.log", Decisive: true},
+			}, hits)
+
+			// The negation cannot re-include a file below an excluded directory.
+			hits, err = m.Explain(context.Background(), "logs/keep.log", false)
+			require.NoError(t, err)
+			require.Equal(t, []match.Hit{
+				{Type: match.GitIgnore, Source: match.Source{Pattern: "logs/", Line: 3}, Path: "logs/", Decisive: true},
+			}, hits)
+
+			hits, err = m.Explain(context.Background(), "src/main.go", false)
+			require.NoError(t, err)
+			require.Empty(t, hits)
+		}
+	}
+}
+
+func TestReader(t *testing.T) {
+	for _, newMatcher := range []func(Options) (*Matcher, error){NewMatcher, NewParallelMatcher} {
+		m, err := newMatcher(Options{
+			Patterns:   []string{"*.log"},
+			Reader:     strings.NewReader("# defaults\n*.tmp\n!keep.log\n"),
+			ReaderName: "https://example.com/gitignore",
+		})
+		require.NoError(t, err)
+
+		res, err := m.Match2(context.Background(), "a.tmp")
+		require.NoError(t, err)
+		require.Equal(t, match.Source{Pattern: "*.tmp", File: "https://example.com/gitignore", Line: 2}, res.Source())
+
+		// The reader ranks above the patterns.
+		ok, err := m.Match(context.Background(), "keep.log")
+		require.NoError(t, err)
+		require.False(t, ok)
+	}
+
+	_, err := NewMatcher(Options{Reader: iotest.ErrReader(errors.New("boom"))})
+	require.ErrorContains(t, err, "boom")
+}
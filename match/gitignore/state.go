@@ -0,0 +1,96 @@
+package gitignore
+
+import (
+	"fmt"
+	"slices"
+)
+
+// State is the parsed form of the rules of a Matcher. FromState rebuilds the
+// Matcher from it without reading and parsing the ignore sources again.
+type State struct {
+	Engine     Engine
+	IgnoreCase bool
+	Parallel   bool
+	// Files are the files and directories the rules were loaded from, see
+	// Matcher.Files.
+	Files  []string
+	Layers []LayerState
+}
+
+// LayerState holds the rules of a single ignore source, from the lowest to the
+// highest precedence.
+type LayerState struct {
+	// Base is the slash terminated directory the rules are relative to, empty for
+	// the root.
+	Base  string
+	Rules []RuleState
+}
+
+// RuleState is a parsed gitignore line.
+type RuleState struct {
+	Source string
+	// Expr is the RE2 translation of the line for EngineRegex, Pattern its
+	// wildmatch pattern for EngineWildmatch.
+	Expr     string
+	Pattern  string
+	Basename bool
+	Negate   bool
+	DirOnly  bool
+	File     string
+	Line     int
+}
+
+// State returns the parsed rules of the matcher.
+func (gi *Matcher) State() State {
+	s := State{Engine: gi.engine, IgnoreCase: gi.ignoreCase, Parallel: gi.parallel, Files: slices.Clone(gi.files)}
+	for _, rs := range gi.layers {
+		layer := LayerState{Base: rs.base, Rules: make([]RuleState, 0, len(rs.rules))}
+		for _, r := range rs.rules {
+			layer.Rules = append(layer.Rules, RuleState{
+				Source:   r.src,
+				Expr:     r.rePat,
+				Pattern:  r.pattern,
+				Basename: r.basename,
+				Negate:   r.negate,
+				DirOnly:  r.dirOnly,
+				File:     r.file,
+				Line:     r.line,
+			})
+		}
+		s.Layers = append(s.Layers, layer)
+	}
+	return s
+}
+
+// FromState rebuilds a matcher from the parsed rules returned by State. Only the
+// regular expressions of EngineRegex rules are compiled again.
+func FromState(s State) (*Matcher, error) {
+	if s.Engine != EngineRegex && s.Engine != EngineWildmatch {
+		return nil, fmt.Errorf("unknown engine %d", s.Engine)
+	}
+
+	gi := &Matcher{engine: s.Engine, ignoreCase: s.IgnoreCase, parallel: s.Parallel, files: slices.Clone(s.Files)}
+	for _, layer := range s.Layers {
+		rs := &ruleSet{base: layer.Base, ignoreCase: s.IgnoreCase, rules: make([]*rule, 0, len(layer.Rules))}
+		for _, r := range layer.Rules {
+			rs.rules = append(rs.rules, &rule{
+				src:      r.Source,
+				rePat:    r.Expr,
+				pattern:  r.Pattern,
+				basename: r.Basename,
+				negate:   r.Negate,
+				dirOnly:  r.DirOnly,
+				file:     r.File,
+				line:     r.Line,
+			})
+		}
+		if s.Engine == EngineRegex {
+			if err := rs.compile(s.Parallel); err != nil {
+				return nil, err
+			}
+		}
+		gi.layers = append(gi.layers, rs)
+		gi.rules = append(gi.rules, rs.rules...)
+	}
+	return gi, nil
+}
@@ -0,0 +1,71 @@
+package gitignore
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"path"
+)
+
+// FileName is the name of the per-directory ignore files picked up under Options.Root.
+const FileName = ".gitignore"
+
+// loadTree walks root of fsys and adds a rule set for every FileName found, scoped to
+// the directory that contains it. Like git, it does not descend into the .git
+// directory or into directories that are already ignored by the rules loaded so far.
+// name maps the path of an ignore file in fsys to the file reported in match sources.
+func (gi *Matcher) loadTree(fsys fs.FS, root string, name func(string) string, parallel bool) error {
+	return gi.loadDir(&tree{fsys: fsys, name: name, parallel: parallel}, root, "")
+}
+
+// tree is a directory tree ignore files are loaded from.
+type tree struct {
+	fsys     fs.FS
+	name     func(string) string
+	parallel bool
+}
+
+// loadDir loads the ignore file of dir, if any, before visiting its subdirectories
+// so that it also applies to them. rel is the slash separated path of dir relative
+// to the root with a trailing slash, empty for the root itself.
+func (gi *Matcher) loadDir(t *tree, dir, rel string) error {
+	entries, err := fs.ReadDir(t.fsys, dir)
+	if err != nil {
+		return err
+	}
+	gi.files = append(gi.files, t.name(dir))
+
+	for _, e := range entries {
+		if e.Name() != FileName || e.IsDir() {
+			continue
+		}
+		file := path.Join(dir, e.Name())
+		patterns, err := readFS(t.fsys, file)
+		if err != nil {
+			return err
+		}
+		rs, err := gi.newRuleSet(rel, []source{{path: t.name(file), lines: patterns}}, t.parallel)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path.Join(rel, e.Name()), err)
+		}
+		gi.layers = append(gi.layers, rs)
+		gi.files = append(gi.files, t.name(file))
+	}
+
+	for _, e := range entries {
+		if !e.IsDir() || e.Name() == ".git" {
+			continue
+		}
+		sub := rel + e.Name()
+		if res, err := gi.match(context.Background(), sub, true /*isDir*/); err != nil {
+			return err
+		} else if res.Ok() {
+			continue
+		}
+		if err := gi.loadDir(t, path.Join(dir, e.Name()), sub+"/"); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
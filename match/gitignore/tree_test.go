@@ -0,0 +1,163 @@
+package gitignore
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vbhat161/go-path-ignore/match"
+)
+
+func TestRootMatcher(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		".gitignore":          "*.log\n/out\nvendor/\n",
+		"sub/.gitignore":      "!keep.log\nlocal/\n/only-here.txt\n",
+		"sub/deep/.gitignore": "keep.log\n",
+		"vendor/.gitignore":   "!*\n",
+	})
+
+	gitAvailable := isGitAvailable(t)
+	if gitAvailable {
+		cmd := exec.Command("git", "init")
+		cmd.Dir = dir
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, "git init cmd run failure: %s", out)
+	}
+
+	matching := []string{
+		"a.log",
+		"sub/a.log",
+		"sub/deep/keep.log",
+		"sub/only-here.txt",
+		"sub/local/f.txt",
+		"out",
+		"out/x",
+		"vendor/a.txt",
+	}
+	nonMatching := []string{
+		"sub/keep.log",
+		"only-here.txt",
+		"sub/x/only-here.txt",
+		"local/f.txt",
+		"sub/out",
+	}
+
+	for _, parallel := range []bool{false, true} {
+		gi, err := newMatcher(Options{Root: dir}, parallel)
+		require.NoError(t, err)
+		require.Len(t, gi.layers, 3, "ignored vendor directory must not be loaded")
+
+		for _, path := range matching {
+			if gitAvailable {
+				checkIgnoreOK, err := matchesGitCheckIgnore(t, path, dir)
+				require.NoError(t, err)
+				require.True(t, checkIgnoreOK, "wrong match expectation - path: %q", path)
+			}
+			matches, err := gi.Match(context.Background(), path)
+			require.NoError(t, err)
+			require.True(t, matches, "should match - path: %q, parallel: %v", path, parallel)
+		}
+		for _, path := range nonMatching {
+			if gitAvailable {
+				checkIgnoreOK, err := matchesGitCheckIgnore(t, path, dir)
+				require.NoError(t, err)
+				require.False(t, checkIgnoreOK, "wrong non-match expectation - path: %q", path)
+			}
+			matches, err := gi.Match(context.Background(), path)
+			require.NoError(t, err)
+			require.False(t, matches, "should not match - path: %q, parallel: %v", path, parallel)
+		}
+	}
+}
+
+func writeTree(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o700))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	}
+}
+
+func TestRootMatcherSource(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		".gitignore":     "# build output\n\n*.log\n",
+		"sub/.gitignore": "!keep.log\n*.tmp\n",
+	})
+
+	for _, newMatcher := range []func(Options) (*Matcher, error){NewMatcher, NewParallelMatcher} {
+		m, err := newMatcher(Options{Patterns: []string{"*.bak"}, Root: dir})
+		require.NoError(t, err)
+
+		res, err := m.Match2(context.Background(), "sub/a.log")
+		require.NoError(t, err)
+		require.Equal(t, match.Source{Pattern: "*.log", File: filepath.Join(dir, ".gitignore"), Line: 3}, res.Source())
+
+		res, err = m.Match2(context.Background(), "sub/a.tmp")
+		require.NoError(t, err)
+		require.Equal(t, match.Source{Pattern: "*.tmp", File: filepath.Join(dir, "sub", ".gitignore"), Line: 2}, res.Source())
+
+		res, err = m.Match2(context.Background(), "a.bak")
+		require.NoError(t, err)
+		require.Equal(t, match.Source{Pattern: "*.bak", Line: 1}, res.Source())
+
+		res, err = m.Match2(context.Background(), "sub/keep.log")
+		require.NoError(t, err)
+		require.False(t, res.Ok())
+		require.Equal(t, match.Source{}, res.Source())
+
+		hits, err := m.Explain(context.Background(), "sub/keep.log", false)
+		require.NoError(t, err)
+		require.Len(t, hits, 2)
+		require.Equal(t, match.Source{Pattern: "!keep.log", File: filepath.Join(dir, "sub", ".gitignore"), Line: 1, Negate: true}, hits[1].Source)
+	}
+}
+
+func TestRootMatcherFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"repo/.gitignore":        {Data: []byte("*.log\n/out\n")},
+		"repo/sub/.gitignore":    {Data: []byte("!keep.log\n")},
+		"repo/out/.gitignore":    {Data: []byte("!*\n")},
+		"defaults/ignore.txt":    {Data: []byte("*.bak\n")},
+		"repo/sub/deep/file.txt": {},
+	}
+
+	for _, newMatcher := range []func(Options) (*Matcher, error){NewMatcher, NewParallelMatcher} {
+		m, err := newMatcher(Options{FS: fsys, Root: "repo", FilePath: "defaults/ignore.txt"})
+		require.NoError(t, err)
+
+		for path, want := range map[string]bool{
+			"a.log":        true,
+			"sub/a.log":    true,
+			"sub/keep.log": false,
+			"out/x":        true,
+			"a.bak":        true,
+			"sub/a.txt":    false,
+		} {
+			res, err := m.Match2(context.Background(), path)
+			require.NoError(t, err)
+			require.Equal(t, want, res.Ok(), path)
+		}
+
+		res, err := m.Match2(context.Background(), "sub/a.log")
+		require.NoError(t, err)
+		require.Equal(t, match.Source{Pattern: "*.log", File: "repo/.gitignore", Line: 1}, res.Source())
+
+		res, err = m.Match2(context.Background(), "a.bak")
+		require.NoError(t, err)
+		require.Equal(t, match.Source{Pattern: "*.bak", File: "defaults/ignore.txt", Line: 1}, res.Source())
+	}
+
+	_, err := NewMatcher(Options{FS: fsys, Root: "repo", ExcludeStandard: true})
+	require.Error(t, err)
+
+	_, err = NewMatcher(Options{FS: fsys, FilePath: "missing.txt"})
+	require.ErrorIs(t, err, fs.ErrNotExist)
+}
@@ -3,7 +3,8 @@ package glob
 import (
 	"context"
 	"fmt"
-	"sync"
+	"slices"
+	"strings"
 
 	"github.com/gobwas/glob"
 	"github.com/vbhat161/go-path-ignore/match"
@@ -25,41 +26,64 @@ func (c CompileError) Error() string {
 /*
 * This is a convenient wrapper around github.com/gobwas/glob
 * that allows for both sequential and parallel glob matching.
-* The glob patterns are compiled only once and reused.
+* The glob patterns are compiled only once and reused. In parallel
+* mode they are translated to RE2 and matched with a single RE2 set.
  */
 type Matcher struct {
-	globs    []glob.Glob
-	parallel bool
+	globs []glob.Glob
+	set   *match.RE2Set
+	// exprs are the RE2 translations of the globs in parallel mode.
+	exprs []string
+	// patterns are the source patterns of globs, as given in Options, and lines
+	// their 1-based positions in Options.Patterns or Options.RawPatterns. raw is
+	// set for the latter.
+	patterns   []string
+	lines      []int
+	raw        []bool
+	parallel   bool
+	ignoreCase bool
 }
 
 type Options struct {
 	Patterns    []string
 	RawPatterns []string
+	// IgnoreCase makes the patterns match case-insensitively. Patterns and paths
+	// are both lower-cased before matching.
+	IgnoreCase bool
 }
 
 func NewMatcher(opts Options) (*Matcher, []error) {
 	globs := make([]glob.Glob, 0, len(opts.Patterns))
+	patterns := make([]string, 0, len(opts.Patterns))
+	lines := make([]int, 0, len(opts.Patterns))
+	raw := make([]bool, 0, len(opts.Patterns))
 	var errs []error
-	for _, p := range opts.Patterns {
-		g, err := glob.Compile(p)
+	for i, p := range opts.Patterns {
+		g, err := compile(p, opts.IgnoreCase)
 		if err != nil {
 			errs = append(errs, newCompileError(p, err))
 			continue
 		}
 		globs = append(globs, g)
+		patterns = append(patterns, p)
+		lines = append(lines, i+1)
+		raw = append(raw, false)
 	}
 
-	for _, p := range opts.RawPatterns {
+	for i, p := range opts.RawPatterns {
 		escaped := glob.QuoteMeta(p)
-		g, err := glob.Compile(escaped)
+		g, err := compile(escaped, opts.IgnoreCase)
 		if err != nil {
 			errs = append(errs, newCompileError(p, err))
 			continue
 		}
 		globs = append(globs, g)
+		patterns = append(patterns, p)
+		lines = append(lines, i+1)
+		raw = append(raw, true)
 	}
 
-	return &Matcher{globs: globs, parallel: false}, errs
+	return &Matcher{globs: globs, patterns: patterns, lines: lines, raw: raw, parallel: false, ignoreCase: opts.IgnoreCase}, errs
 }
 
 func NewStrictMatcher(opts Options) (*Matcher, error) {
@@ -72,22 +96,48 @@ func NewStrictParallelMatcher(opts Options) (*Matcher, error) {
 
 func newStrictMatcher(opts Options, llel bool) (*Matcher, error) {
 	globs := make([]glob.Glob, 0, len(opts.Patterns))
-	for _, p := range opts.Patterns {
-		g, err := glob.Compile(p)
+	patterns := make([]string, 0, len(opts.Patterns))
+	lines := make([]int, 0, len(opts.Patterns))
+	raw := make([]bool, 0, len(opts.Patterns))
+	for i, p := range opts.Patterns {
+		g, err := compile(p, opts.IgnoreCase)
 		if err != nil {
 			return nil, newCompileError(p, err)
 		}
 		globs = append(globs, g)
+		patterns = append(patterns, p)
+		lines = append(lines, i+1)
+		raw = append(raw, false)
 	}
-	for _, p := range opts.RawPatterns {
+	for i, p := range opts.RawPatterns {
 		escaped := glob.QuoteMeta(p)
-		g, err := glob.Compile(escaped)
+		g, err := compile(escaped, opts.IgnoreCase)
 		if err != nil {
 			return nil, newCompileError(p, err)
 		}
 		globs = append(globs, g)
+		patterns = append(patterns, p)
+		lines = append(lines, i+1)
+		raw = append(raw, true)
 	}
-	return &Matcher{globs: globs, parallel: llel}, nil
+	m := &Matcher{globs: globs, patterns: patterns, lines: lines, raw: raw, parallel: llel, ignoreCase: opts.IgnoreCase}
+	if llel && len(globs) > 0 {
+		exprs, err := m.Exprs()
+		if err != nil {
+			return nil, err
+		}
+		if err := m.compileSet(exprs); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+func compile(pattern string, ignoreCase bool) (glob.Glob, error) {
+	if ignoreCase {
+		pattern = strings.ToLower(pattern)
+	}
+	return glob.Compile(pattern)
 }
 
 func (m *Matcher) Type() match.Type {
@@ -100,15 +150,15 @@ func (m *Matcher) Match(ctx context.Context, path string) (bool, error) {
 }
 
 type result struct {
-	src string
+	source match.Source
 }
 
 func (r result) Ok() bool {
-	return r.src != ""
+	return r.source.Line > 0
 }
 
 func (r result) Src() string {
-	return r.src
+	return r.source.Pattern
 }
 
 func (r result) Type() match.Type {
@@ -116,30 +166,39 @@ func (r result) Type() match.Type {
 }
 
 func (r result) String() string {
-	return fmt.Sprintf("%s:%s", r.Type(), r.src)
+	return fmt.Sprintf("%s:%s", r.Type(), r.source.Pattern)
+}
+
+func (r result) Source() match.Source {
+	return r.source
 }
 
+// Match2 is like Match but also reports the glob that matched, the first one in
+// pattern order.
 func (m *Matcher) Match2(ctx context.Context, path string) (match.MatchInfo, error) {
 	if ctx.Err() != nil {
 		return match.NoMatch, ctx.Err()
 	}
 
 	res := result{}
+	if m.ignoreCase {
+		path = strings.ToLower(path)
+	}
 	if m.parallel {
-		if path, err := m.concurrentMatch(ctx, path); err != nil {
-			return res, err
-		} else {
-			res.src = path
-			return res, nil
+		if m.set != nil {
+			if idx := m.set.MatchIndices(path); len(idx) > 0 {
+				res.source = m.source(idx[0])
+			}
 		}
+		return res, nil
 	} else {
-		for _, g := range m.globs {
+		for i, g := range m.globs {
 			select {
 			case <-ctx.Done():
 				return res, ctx.Err()
 			default:
 				if g.Match(path) {
-					res.src = path
+					res.source = m.source(i)
 					return res, nil
 				}
 			}
@@ -148,37 +207,93 @@ func (m *Matcher) Match2(ctx context.Context, path string) (match.MatchInfo, err
 	}
 }
 
-func (m *Matcher) concurrentMatch(ctx context.Context, path string) (string, error) {
-	foundSrc := make(chan string, 1)
+func (m *Matcher) compileSet(exprs []string) error {
+	set, err := match.NewRE2Set(exprs)
+	if err != nil {
+		return fmt.Errorf("parallel: re2 set - %w", err)
+	}
+	m.set, m.exprs = set, exprs
+	return nil
+}
 
-	matchCtx, stopMatch := context.WithCancel(ctx)
-	defer stopMatch()
+// Exprs returns the globs translated to RE2 expressions, see Translate.
+func (m *Matcher) Exprs() ([]string, error) {
+	if m.exprs != nil {
+		return slices.Clone(m.exprs), nil
+	}
 
-	var wg sync.WaitGroup
-	for _, g := range m.globs {
-		wg.Go(func() {
-			if matchCtx.Err() != nil {
-				return
+	exprs := make([]string, 0, len(m.patterns))
+	for i, p := range m.patterns {
+		if m.raw[i] {
+			p = glob.QuoteMeta(p)
+		}
+		if m.ignoreCase {
+			p = strings.ToLower(p)
+		}
+		expr, err := Translate(p)
+		if err != nil {
+			return nil, newCompileError(m.patterns[i], err)
+		}
+		if m.ignoreCase {
+			expr = "(?i)" + expr
+		}
+		exprs = append(exprs, expr)
+	}
+	return exprs, nil
+}
+
+// MatchSet is like Match2, with the globs matching path given by hits.
+func (m *Matcher) MatchSet(ctx context.Context, path string, _ bool, hits func(string) []int) (match.MatchInfo, error) {
+	res := result{}
+	if ctx.Err() != nil {
+		return res, ctx.Err()
+	}
+	if idx := hits(path); len(idx) > 0 {
+		res.source = m.source(idx[0])
+	}
+	return res, nil
+}
+
+func (m *Matcher) source(i int) match.Source {
+	return match.Source{Pattern: m.patterns[i], Line: m.lines[i]}
+}
+
+// MatchEntry is like Match2. Glob patterns have no directory-only syntax, so isDir
+// does not affect the result.
+func (m *Matcher) MatchEntry(ctx context.Context, path string, _ bool) (match.MatchInfo, error) {
+	return m.Match2(ctx, path)
+}
+
+// Explain returns every glob matching path, in pattern order. The first one decides.
+func (m *Matcher) Explain(ctx context.Context, path string, _ bool) ([]match.Hit, error) {
+	subject := path
+	if m.ignoreCase {
+		subject = strings.ToLower(path)
+	}
+
+	var hits []match.Hit
+	if m.parallel {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if m.set != nil {
+			for _, i := range m.set.MatchIndices(subject) {
+				hits = append(hits, match.Hit{Type: match.Glob, Source: m.source(i), Path: path})
 			}
-			if g.Match(path) {
-				select {
-				case <-matchCtx.Done():
-				case foundSrc <- path:
-					stopMatch()
-				}
+		}
+	} else {
+		for i, g := range m.globs {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			if g.Match(subject) {
+				hits = append(hits, match.Hit{Type: match.Glob, Source: m.source(i), Path: path})
 			}
-		})
+		}
 	}
 
-	go func() {
-		wg.Wait()
-		close(foundSrc)
-	}()
-
-	select {
-	case p := <-foundSrc:
-		return p, nil
-	case <-matchCtx.Done():
-		return "", nil
+	if len(hits) > 0 {
+		hits[0].Decisive = true
 	}
+	return hits, nil
 }
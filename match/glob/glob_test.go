@@ -6,6 +6,7 @@ import (
 
 	"github.com/gobwas/glob"
 	"github.com/stretchr/testify/require"
+	"github.com/vbhat161/go-path-ignore/match"
 	"go.uber.org/goleak"
 )
 
@@ -188,6 +189,34 @@ func TestMatch(t *testing.T) {
 			input:    "image.png",
 			expected: false,
 		},
+		{
+			name: "ignore case",
+			options: Options{
+				Patterns:   []string{"*.TXT", "Docs/[A-C]*"},
+				IgnoreCase: true,
+			},
+			input:    "docs/beta.md",
+			expected: true,
+		},
+		{
+			name: "ignore case raw pattern",
+			options: Options{
+				RawPatterns: []string{"README*"},
+				IgnoreCase:  true,
+			},
+			parallel: true,
+			input:    "readme*",
+			expected: true,
+		},
+		{
+			name: "case sensitive by default",
+			options: Options{
+				Patterns: []string{"*.TXT"},
+			},
+			parallel: true,
+			input:    "notes.txt",
+			expected: false,
+		},
 		{
 			name: "context cancelled before match",
 			options: Options{
@@ -244,3 +273,79 @@ func TestMatch(t *testing.T) {
 		})
 	}
 }
+
+func TestExplain(t *testing.T) {
+	for _, newMatcher := range []func(Options) (*Matcher, error){NewStrictMatcher, NewStrictParallelMatcher} {
+		m, err := newMatcher(Options{Patterns: []string{"*.go", "*.md", "vendor/**"}})
+		require.NoError(t, err)
+
+		hits, err := m.Explain(context.Background(), "vendor/x.go", false)
+		require.NoError(t, err)
+		require.Len(t, hits, 2)
+		require.Equal(t, "*.go", hits[0].Pattern)
+		require.True(t, hits[0].Decisive)
+		require.Equal(t, "vendor/**", hits[1].Pattern)
+		require.False(t, hits[1].Decisive)
+
+		hits, err = m.Explain(context.Background(), "main.c", false)
+		require.NoError(t, err)
+		require.Empty(t, hits)
+	}
+}
+
+func TestSource(t *testing.T) {
+	m, err := NewStrictMatcher(Options{Patterns: []string{"*.md", "*.go"}, RawPatterns: []string{"[x].txt"}})
+	require.NoError(t, err)
+
+	res, err := m.Match2(context.Background(), "main.go")
+	require.NoError(t, err)
+	require.Equal(t, "*.go", res.Src())
+	require.Equal(t, match.Source{Pattern: "*.go", Line: 2}, res.Source())
+
+	res, err = m.Match2(context.Background(), "[x].txt")
+	require.NoError(t, err)
+	require.Equal(t, match.Source{Pattern: "[x].txt", Line: 1}, res.Source())
+
+	// Invalid patterns are skipped, the position in Options is still reported.
+	m, errs := NewMatcher(Options{Patterns: []string{"[", "*.go"}})
+	require.Len(t, errs, 1)
+	res, err = m.Match2(context.Background(), "main.go")
+	require.NoError(t, err)
+	require.Equal(t, match.Source{Pattern: "*.go", Line: 2}, res.Source())
+
+	m, err = NewStrictParallelMatcher(Options{Patterns: []string{"*.go"}})
+	require.NoError(t, err)
+	res, err = m.Match2(context.Background(), "main.go")
+	require.NoError(t, err)
+	require.Equal(t, match.Source{Pattern: "*.go", Line: 1}, res.Source())
+
+	res, err = m.Match2(context.Background(), "main.c")
+	require.NoError(t, err)
+	require.False(t, res.Ok())
+	require.Equal(t, match.Source{}, res.Source())
+}
+
+func TestParallelMatchesSequential(t *testing.T) {
+	for _, ignoreCase := range []bool{false, true} {
+		opts := Options{
+			Patterns:    []string{"*.go", "**/test/**", "file-?.txt", "[a-c].md", "[!a-c]x", "{src,lib}/*.{js,ts}", `\*.bak`},
+			RawPatterns: []string{"[x].txt", "a{b}"},
+			IgnoreCase:  ignoreCase,
+		}
+		seq, err := NewStrictMatcher(opts)
+		require.NoError(t, err)
+		llel, err := NewStrictParallelMatcher(opts)
+		require.NoError(t, err)
+
+		for _, path := range []string{
+			"main.go", "cmd/Main.GO", "a/test/b", "test/a", "file-1.txt", "file-10.txt", "B.md", "d.md",
+			"dx", "bx", "src/a.js", "Lib/b/c.TS", "src/a.css", "*.bak", "x.bak", "[x].txt", "x.txt", "a{b}", "ab",
+		} {
+			want, err := seq.Match2(context.Background(), path)
+			require.NoError(t, err)
+			got, err := llel.Match2(context.Background(), path)
+			require.NoError(t, err)
+			require.Equal(t, want.Source(), got.Source(), "ignoreCase %v, path %q", ignoreCase, path)
+		}
+	}
+}
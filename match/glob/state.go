@@ -0,0 +1,73 @@
+package glob
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/gobwas/glob"
+)
+
+// State is the compiled form of the options of a Matcher. FromState rebuilds the
+// Matcher from it, without translating the globs again in parallel mode.
+type State struct {
+	// Patterns are the globs as given in Options, Lines their 1-based positions in
+	// Options.Patterns or Options.RawPatterns and Raw is set for the latter.
+	Patterns []string
+	Lines    []int
+	Raw      []bool
+	// Exprs are the RE2 translations of the globs in parallel mode.
+	Exprs      []string
+	IgnoreCase bool
+	Parallel   bool
+}
+
+// State returns the globs of the matcher and, in parallel mode, their translations.
+func (m *Matcher) State() State {
+	return State{
+		Patterns:   slices.Clone(m.patterns),
+		Lines:      slices.Clone(m.lines),
+		Raw:        slices.Clone(m.raw),
+		Exprs:      slices.Clone(m.exprs),
+		IgnoreCase: m.ignoreCase,
+		Parallel:   m.parallel,
+	}
+}
+
+// FromState rebuilds a matcher from the globs returned by State.
+func FromState(s State) (*Matcher, error) {
+	if len(s.Lines) != len(s.Patterns) || len(s.Raw) != len(s.Patterns) {
+		return nil, fmt.Errorf("%d patterns with %d lines and %d raw flags", len(s.Patterns), len(s.Lines), len(s.Raw))
+	}
+
+	m := &Matcher{
+		patterns:   slices.Clone(s.Patterns),
+		lines:      slices.Clone(s.Lines),
+		raw:        slices.Clone(s.Raw),
+		parallel:   s.Parallel,
+		ignoreCase: s.IgnoreCase,
+	}
+	if s.Parallel {
+		if len(s.Exprs) != len(s.Patterns) {
+			return nil, fmt.Errorf("%d patterns with %d expressions", len(s.Patterns), len(s.Exprs))
+		}
+		if len(s.Exprs) > 0 {
+			if err := m.compileSet(slices.Clone(s.Exprs)); err != nil {
+				return nil, err
+			}
+		}
+		return m, nil
+	}
+
+	m.globs = make([]glob.Glob, 0, len(s.Patterns))
+	for i, p := range s.Patterns {
+		if s.Raw[i] {
+			p = glob.QuoteMeta(p)
+		}
+		g, err := compile(p, s.IgnoreCase)
+		if err != nil {
+			return nil, newCompileError(s.Patterns[i], err)
+		}
+		m.globs = append(m.globs, g)
+	}
+	return m, nil
+}
@@ -0,0 +1,99 @@
+package glob
+
+import (
+	"fmt"
+	"regexp/syntax"
+	"strings"
+
+	globsyntax "github.com/gobwas/glob/syntax"
+	"github.com/gobwas/glob/syntax/ast"
+)
+
+// Translate returns an RE2 expression matching the same paths as the glob pattern
+// compiled by this package, without separators: "*" and "**" match any sequence
+// of characters, "?" any single character, "[...]" and "[!...]" a character of a
+// list or range, and "{a,b}" one of the alternatives. The pattern is parsed by
+// gobwas/glob itself, so that escapes and malformed patterns are handled alike.
+func Translate(pattern string) (string, error) {
+	tree, err := globsyntax.Parse(pattern)
+	if err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+	sb.WriteString(`^(?s:`)
+	if err := translate(&sb, tree); err != nil {
+		return "", err
+	}
+	sb.WriteString(`)$`)
+	return sb.String(), nil
+}
+
+func translate(sb *strings.Builder, node *ast.Node) error {
+	switch node.Kind {
+	case ast.KindNothing:
+	case ast.KindPattern:
+		for _, child := range node.Children {
+			if err := translate(sb, child); err != nil {
+				return err
+			}
+		}
+	case ast.KindAnyOf:
+		sb.WriteString(`(?:`)
+		for i, child := range node.Children {
+			if i > 0 {
+				sb.WriteByte('|')
+			}
+			if err := translate(sb, child); err != nil {
+				return err
+			}
+		}
+		sb.WriteByte(')')
+	case ast.KindText:
+		for _, r := range node.Value.(ast.Text).Text {
+			writeChar(sb, r)
+		}
+	case ast.KindAny, ast.KindSuper:
+		sb.WriteString(`.*`)
+	case ast.KindSingle:
+		sb.WriteByte('.')
+	case ast.KindList:
+		list := node.Value.(ast.List)
+		writeClassOpen(sb, list.Not)
+		for _, r := range list.Chars {
+			writeChar(sb, r)
+		}
+		sb.WriteByte(']')
+	case ast.KindRange:
+		rng := node.Value.(ast.Range)
+		writeClassOpen(sb, rng.Not)
+		writeChar(sb, rng.Lo)
+		sb.WriteByte('-')
+		writeChar(sb, rng.Hi)
+		sb.WriteByte(']')
+	default:
+		return fmt.Errorf("translate glob: unknown node %s", node.Kind)
+	}
+	return nil
+}
+
+func writeClassOpen(sb *strings.Builder, not bool) {
+	if not {
+		sb.WriteString(`[^`)
+	} else {
+		sb.WriteByte('[')
+	}
+}
+
+// writeChar writes r so that it is taken literally, inside and outside of
+// character classes.
+func writeChar(sb *strings.Builder, r rune) {
+	switch {
+	case r < 0x80 && syntax.IsWordChar(r):
+		sb.WriteRune(r)
+	case r < 0x80:
+		fmt.Fprintf(sb, `\x%02x`, r)
+	default:
+		fmt.Fprintf(sb, `\x{%x}`, r)
+	}
+}
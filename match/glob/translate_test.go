@@ -0,0 +1,60 @@
+package glob
+
+import (
+	"testing"
+
+	"github.com/gobwas/glob"
+	"github.com/stretchr/testify/require"
+	regexp "github.com/wasilibs/go-re2"
+)
+
+func TestTranslate(t *testing.T) {
+	tests := []struct {
+		pattern  string
+		matching []string
+		other    []string
+	}{
+		{pattern: "*.go", matching: []string{"main.go", "cmd/main.go", ".go"}, other: []string{"main.go.txt", "main.c"}},
+		{pattern: "**/test/**", matching: []string{"a/test/b", "/test/"}, other: []string{"test/a"}},
+		{pattern: "file-?.txt", matching: []string{"file-1.txt", "file-/.txt"}, other: []string{"file-10.txt"}},
+		{pattern: "[abc].md", matching: []string{"a.md", "c.md"}, other: []string{"d.md", "ab.md"}},
+		{pattern: "[!abc].md", matching: []string{"d.md", "/.md"}, other: []string{"a.md"}},
+		{pattern: "[a-c]x", matching: []string{"bx"}, other: []string{"dx"}},
+		{pattern: "[!a-c]x", matching: []string{"dx"}, other: []string{"bx"}},
+		{pattern: "{src,lib}/*.{js,ts}", matching: []string{"src/a.js", "lib/b/c.ts"}, other: []string{"test/a.js", "src/a.css"}},
+		{pattern: "{a,{b,c}d}", matching: []string{"a", "bd", "cd"}, other: []string{"ad", "b"}},
+		{pattern: `\*.txt`, matching: []string{"*.txt"}, other: []string{"a.txt"}},
+		{pattern: `a\{b\}`, matching: []string{"a{b}"}, other: []string{"ab"}},
+		{pattern: "a+b(c)|^$.", matching: []string{"a+b(c)|^$."}, other: []string{"aab(c)|^$."}},
+		{pattern: "[.^]]", matching: []string{".]", "^]"}, other: []string{"a]"}},
+		{pattern: "with space", matching: []string{"with space"}, other: []string{"withspace"}},
+		{pattern: "", matching: []string{""}, other: []string{"a"}},
+		{pattern: "línea*", matching: []string{"línea.txt"}, other: []string{"linea.txt"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			expr, err := Translate(tt.pattern)
+			require.NoError(t, err)
+			re, err := regexp.Compile(expr)
+			require.NoError(t, err, expr)
+			g := glob.MustCompile(tt.pattern)
+
+			for _, path := range tt.matching {
+				require.True(t, g.Match(path), "glob %q should match %q", tt.pattern, path)
+				require.True(t, re.MatchString(path), "%s should match %q", expr, path)
+			}
+			for _, path := range tt.other {
+				require.False(t, g.Match(path), "glob %q should not match %q", tt.pattern, path)
+				require.False(t, re.MatchString(path), "%s should not match %q", expr, path)
+			}
+		})
+	}
+}
+
+func TestTranslateInvalid(t *testing.T) {
+	for _, pattern := range []string{"[", "[]", "[z-a]", "[a-]"} {
+		_, err := Translate(pattern)
+		require.Error(t, err, pattern)
+	}
+}
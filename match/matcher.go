@@ -31,12 +31,55 @@ type MatchInfo interface {
 	Src() string
 	Type() Type
 	String() string
+	// Source tells where the pattern responsible for the match was defined. It is
+	// the zero Source if nothing matched.
+	Source() Source
+}
+
+// Source is the origin of a pattern.
+type Source struct {
+	// Pattern is the pattern as written, e.g. the raw gitignore line.
+	Pattern string
+	// File is the file the pattern was read from, empty for patterns given in the
+	// options.
+	File string
+	// Line is the 1-based line of the pattern in File or, for patterns given in the
+	// options, its 1-based position in the pattern list.
+	Line int
+	// Negate is set for gitignore patterns that re-include paths.
+	Negate bool
 }
 
 type PathMatcher interface {
 	Type() Type
 	Match(ctx context.Context, path string) (bool, error)
 	Match2(ctx context.Context, path string) (MatchInfo, error)
+	// MatchEntry is like Match2 but takes whether the path names a directory
+	// instead of inferring it from a trailing slash.
+	MatchEntry(ctx context.Context, path string, isDir bool) (MatchInfo, error)
+	// Explain returns every rule matching the path, in evaluation order, with the
+	// rule deciding the outcome marked as decisive.
+	Explain(ctx context.Context, path string, isDir bool) ([]Hit, error)
+}
+
+// Hit is a rule that matched while explaining a path.
+type Hit struct {
+	Type Type
+	Source
+	// Path is the path the rule matched, either the explained path or, for
+	// gitignore rules, one of its parent directories.
+	Path string
+	// Decisive is set for the hit that determined the outcome. Other hits were
+	// overridden by a later rule or by an earlier strategy.
+	Decisive bool
+}
+
+// Explanation lists every rule that matched a path, across strategies and in
+// evaluation order, along with the final decision.
+type Explanation struct {
+	Path   string
+	Hits   []Hit
+	Result MatchInfo
 }
 
 type noMatch struct{}
@@ -56,3 +99,21 @@ func (noMatch) Type() Type {
 func (noMatch) String() string {
 	return ""
 }
+
+func (noMatch) Source() Source {
+	return Source{}
+}
+
+// SetMatcher is implemented by matchers whose rules can be lowered to RE2
+// expressions, so that they can be evaluated together with the rules of other
+// matchers by a single RE2Set.
+type SetMatcher interface {
+	PathMatcher
+	// Exprs returns the RE2 expressions of the rules, matched against whole slash
+	// separated paths.
+	Exprs() ([]string, error)
+	// MatchSet is like MatchEntry, but takes the rules matching a path from hits
+	// instead of evaluating them. hits returns the indices into Exprs of the
+	// expressions matching a path, in ascending order.
+	MatchSet(ctx context.Context, path string, isDir bool, hits func(string) []int) (MatchInfo, error)
+}
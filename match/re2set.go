@@ -2,6 +2,7 @@ package match
 
 import (
 	"fmt"
+	"slices"
 
 	re2exp "github.com/wasilibs/go-re2/experimental"
 )
@@ -31,3 +32,10 @@ func (s *RE2Set) Matches(path string) (bool, string) {
 	}
 	return true, s.src[res[0]]
 }
+
+// MatchIndices returns the indices of all patterns that match path, in ascending order.
+func (s *RE2Set) MatchIndices(path string) []int {
+	res := s.set.FindAllString(path, -1)
+	slices.Sort(res)
+	return res
+}
@@ -3,6 +3,7 @@ package regex
 import (
 	"context"
 	"fmt"
+	"slices"
 	"strings"
 
 	"github.com/vbhat161/go-path-ignore/match"
@@ -12,11 +13,20 @@ import (
 type Matcher struct {
 	regexps []*regexp.Regexp
 	set     *match.RE2Set
+
+	// patterns are the source patterns, as given in Options, and exprs the
+	// expressions compiled from them.
+	patterns   []string
+	exprs      []string
+	literals   bool
+	ignoreCase bool
 }
 
 type Options struct {
 	Patterns []string
 	Literals bool
+	// IgnoreCase makes the patterns match case-insensitively.
+	IgnoreCase bool
 }
 
 func NewMatcher(opts Options) (*Matcher, error) {
@@ -24,12 +34,16 @@ func NewMatcher(opts Options) (*Matcher, error) {
 		return nil, fmt.Errorf("atleast one pattern required for regex matcher")
 	}
 
+	src := opts.Patterns
 	var literalRegex string
 	if opts.Literals {
 		quoted := quotePatterns(opts.Patterns)
 		literalRegex = strings.Join(quoted, "|")
 		opts.Patterns = []string{literalRegex}
 	}
+	if opts.IgnoreCase {
+		opts.Patterns = foldPatterns(opts.Patterns)
+	}
 
 	regexps := make([]*regexp.Regexp, 0, len(opts.Patterns))
 	for _, p := range opts.Patterns {
@@ -39,7 +53,7 @@ func NewMatcher(opts Options) (*Matcher, error) {
 			regexps = append(regexps, re)
 		}
 	}
-	return &Matcher{regexps: regexps}, nil
+	return &Matcher{regexps: regexps, patterns: src, exprs: opts.Patterns, literals: opts.Literals, ignoreCase: opts.IgnoreCase}, nil
 }
 
 func NewParallelMatcher(opts Options) (*Matcher, error) {
@@ -47,18 +61,22 @@ func NewParallelMatcher(opts Options) (*Matcher, error) {
 		return nil, fmt.Errorf("atleast one pattern required for regex matcher")
 	}
 
+	src := opts.Patterns
 	var literalRegex string
 	if opts.Literals {
 		quoted := quotePatterns(opts.Patterns)
 		literalRegex = strings.Join(quoted, "|")
 		opts.Patterns = []string{literalRegex}
 	}
+	if opts.IgnoreCase {
+		opts.Patterns = foldPatterns(opts.Patterns)
+	}
 
 	set, e := match.NewRE2Set(opts.Patterns)
 	if e != nil {
 		return nil, fmt.Errorf("patterns compilation - %w", e)
 	}
-	return &Matcher{set: set}, nil
+	return &Matcher{set: set, patterns: src, exprs: opts.Patterns, literals: opts.Literals, ignoreCase: opts.IgnoreCase}, nil
 }
 
 func (m *Matcher) Type() match.Type {
@@ -73,15 +91,15 @@ func (m *Matcher) Match(ctx context.Context, path string) (bool, error) {
 }
 
 type result struct {
-	src string
+	source match.Source
 }
 
 func (r result) Ok() bool {
-	return r.src != ""
+	return r.source.Line > 0
 }
 
 func (r result) Src() string {
-	return r.src
+	return r.source.Pattern
 }
 
 func (r result) Type() match.Type {
@@ -89,11 +107,16 @@ func (r result) Type() match.Type {
 }
 
 func (r result) String() string {
-	return fmt.Sprintf("%s:%s", r.Type(), r.src)
+	return fmt.Sprintf("%s:%s", r.Type(), r.source.Pattern)
+}
+
+func (r result) Source() match.Source {
+	return r.source
 }
 
 // Matches takes a path and returns whether it is ignored according to the list of
 // ignore patterns. It returns true if the path should be ignored, and false otherwise.
+// The first matching pattern is reported.
 func (m *Matcher) Match2(ctx context.Context, path string) (match.MatchInfo, error) {
 	res := result{}
 	if ctx.Err() != nil {
@@ -101,25 +124,121 @@ func (m *Matcher) Match2(ctx context.Context, path string) (match.MatchInfo, err
 	}
 
 	if m.set != nil {
-		ok, path := m.set.Matches(path)
-		if ok {
-			res.src = path
+		if idx := m.set.MatchIndices(path); len(idx) > 0 {
+			res.source = m.source(path, idx[0])
 		}
 		return res, nil
 	}
 
-	for _, re := range m.regexps {
+	for i, re := range m.regexps {
 		if ctx.Err() != nil {
 			return res, ctx.Err()
 		}
 		if re.MatchString(path) {
-			res.src = path
+			res.source = m.source(path, i)
 			return res, nil
 		}
 	}
 	return res, nil
 }
 
+// Exprs returns the compiled expressions. Literals are compiled into a single
+// expression.
+func (m *Matcher) Exprs() ([]string, error) {
+	return slices.Clone(m.exprs), nil
+}
+
+// MatchSet is like Match2, with the patterns matching path given by hits.
+func (m *Matcher) MatchSet(ctx context.Context, path string, _ bool, hits func(string) []int) (match.MatchInfo, error) {
+	res := result{}
+	if ctx.Err() != nil {
+		return res, ctx.Err()
+	}
+	if idx := hits(path); len(idx) > 0 {
+		res.source = m.source(path, idx[0])
+	}
+	return res, nil
+}
+
+// source returns the origin of the i-th compiled pattern, which matched path.
+// Literals are compiled into a single pattern, the first literal found in path is
+// reported instead.
+func (m *Matcher) source(path string, i int) match.Source {
+	if m.literals {
+		// Case folding of the compiled pattern may differ from strings.ToLower for
+		// some runes, fall back to the first literal then.
+		i = max(0, slices.IndexFunc(m.patterns, func(lit string) bool {
+			return m.containsLiteral(path, lit)
+		}))
+	}
+	return match.Source{Pattern: m.patterns[i], Line: i + 1}
+}
+
+func (m *Matcher) containsLiteral(path, lit string) bool {
+	if m.ignoreCase {
+		return strings.Contains(strings.ToLower(path), strings.ToLower(lit))
+	}
+	return strings.Contains(path, lit)
+}
+
+// MatchEntry is like Match2. Regex patterns have no directory-only syntax, so isDir
+// does not affect the result.
+func (m *Matcher) MatchEntry(ctx context.Context, path string, _ bool) (match.MatchInfo, error) {
+	return m.Match2(ctx, path)
+}
+
+// Explain returns every pattern matching path, in pattern order. The first one
+// decides. Literal patterns are reported individually.
+func (m *Matcher) Explain(ctx context.Context, path string, _ bool) ([]match.Hit, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	var hits []match.Hit
+	add := func(i int) {
+		hits = append(hits, match.Hit{
+			Type:   match.Regex,
+			Source: match.Source{Pattern: m.patterns[i], Line: i + 1},
+			Path:   path,
+		})
+	}
+	switch {
+	case m.literals:
+		for i, lit := range m.patterns {
+			if m.containsLiteral(path, lit) {
+				add(i)
+			}
+		}
+	case m.set != nil:
+		for _, i := range m.set.MatchIndices(path) {
+			add(i)
+		}
+	default:
+		for i, re := range m.regexps {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			if re.MatchString(path) {
+				add(i)
+			}
+		}
+	}
+
+	if len(hits) > 0 {
+		hits[0].Decisive = true
+	}
+	return hits, nil
+}
+
+func foldPatterns(patterns []string) []string {
+	folded := make([]string, 0, len(patterns))
+	for _, p := range patterns {
+		folded = append(folded, "(?i)"+p)
+	}
+
+	return folded
+}
+
 func quotePatterns(patterns []string) []string {
 	quoted := make([]string, 0, len(patterns))
 	for _, p := range patterns {
@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/require"
+	"github.com/vbhat161/go-path-ignore/match"
 )
 
 func TestNewMatcher(t *testing.T) {
@@ -307,3 +308,93 @@ func TestParallelMatch(t *testing.T) {
 		})
 	}
 }
+
+func TestIgnoreCase(t *testing.T) {
+	tests := []struct {
+		name string
+		opts Options
+		path string
+	}{
+		{name: "pattern", opts: Options{Patterns: []string{`\.bak$`}, IgnoreCase: true}, path: "notes.BAK"},
+		{name: "literal", opts: Options{Patterns: []string{"Vendor/"}, Literals: true, IgnoreCase: true}, path: "vendor/x.go"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, newMatcher := range []func(Options) (*Matcher, error){NewMatcher, NewParallelMatcher} {
+				m, err := newMatcher(tt.opts)
+				require.NoError(t, err)
+				ok, err := m.Match(context.Background(), tt.path)
+				require.NoError(t, err)
+				require.True(t, ok)
+
+				tt.opts.IgnoreCase = false
+				m, err = newMatcher(tt.opts)
+				require.NoError(t, err)
+				ok, err = m.Match(context.Background(), tt.path)
+				require.NoError(t, err)
+				require.False(t, ok)
+				tt.opts.IgnoreCase = true
+			}
+		})
+	}
+}
+
+func TestExplain(t *testing.T) {
+	tests := []struct {
+		name string
+		opts Options
+		path string
+		want []string
+	}{
+		{name: "patterns", opts: Options{Patterns: []string{`\.go$`, `^vendor/`, `\.md$`}}, path: "vendor/x.go", want: []string{`\.go$`, `^vendor/`}},
+		{name: "literals", opts: Options{Patterns: []string{"Vendor/", ".go"}, Literals: true, IgnoreCase: true}, path: "vendor/x.go", want: []string{"Vendor/", ".go"}},
+		{name: "no match", opts: Options{Patterns: []string{`\.md$`}}, path: "vendor/x.go"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, newMatcher := range []func(Options) (*Matcher, error){NewMatcher, NewParallelMatcher} {
+				m, err := newMatcher(tt.opts)
+				require.NoError(t, err)
+				hits, err := m.Explain(context.Background(), tt.path, false)
+				require.NoError(t, err)
+
+				var got []string
+				for i, h := range hits {
+					require.Equal(t, i == 0, h.Decisive)
+					require.Equal(t, tt.path, h.Path)
+					got = append(got, h.Pattern)
+				}
+				require.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestSource(t *testing.T) {
+	tests := []struct {
+		name string
+		opts Options
+		path string
+		want match.Source
+	}{
+		{name: "pattern", opts: Options{Patterns: []string{`\.md$`, `\.go$`, `^vendor/`}}, path: "vendor/x.go", want: match.Source{Pattern: `\.go$`, Line: 2}},
+		{name: "literal", opts: Options{Patterns: []string{"node_modules", "Vendor/"}, Literals: true, IgnoreCase: true}, path: "vendor/x.go", want: match.Source{Pattern: "Vendor/", Line: 2}},
+		{name: "no match", opts: Options{Patterns: []string{`\.md$`}}, path: "vendor/x.go"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, newMatcher := range []func(Options) (*Matcher, error){NewMatcher, NewParallelMatcher} {
+				m, err := newMatcher(tt.opts)
+				require.NoError(t, err)
+				res, err := m.Match2(context.Background(), tt.path)
+				require.NoError(t, err)
+				require.Equal(t, tt.want.Line > 0, res.Ok())
+				require.Equal(t, tt.want.Pattern, res.Src())
+				require.Equal(t, tt.want, res.Source())
+			}
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package regex
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/vbhat161/go-path-ignore/match"
+	regexp "github.com/wasilibs/go-re2"
+)
+
+// State is the compiled form of the options of a Matcher. FromState rebuilds the
+// Matcher from it.
+type State struct {
+	// Patterns are the patterns as given in Options, Exprs the expressions
+	// compiled from them.
+	Patterns   []string
+	Exprs      []string
+	Literals   bool
+	IgnoreCase bool
+	Parallel   bool
+}
+
+// State returns the patterns of the matcher and their expressions.
+func (m *Matcher) State() State {
+	return State{
+		Patterns:   slices.Clone(m.patterns),
+		Exprs:      slices.Clone(m.exprs),
+		Literals:   m.literals,
+		IgnoreCase: m.ignoreCase,
+		Parallel:   m.set != nil,
+	}
+}
+
+// FromState rebuilds a matcher from the expressions returned by State.
+func FromState(s State) (*Matcher, error) {
+	if len(s.Exprs) == 0 {
+		return nil, fmt.Errorf("atleast one pattern required for regex matcher")
+	}
+
+	m := &Matcher{patterns: slices.Clone(s.Patterns), exprs: slices.Clone(s.Exprs), literals: s.Literals, ignoreCase: s.IgnoreCase}
+	if s.Parallel {
+		set, err := match.NewRE2Set(m.exprs)
+		if err != nil {
+			return nil, fmt.Errorf("patterns compilation - %w", err)
+		}
+		m.set = set
+		return m, nil
+	}
+
+	for _, p := range m.exprs {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, fmt.Errorf("pattern(%s) compilation - %w", p, err)
+		}
+		m.regexps = append(m.regexps, re)
+	}
+	return m, nil
+}
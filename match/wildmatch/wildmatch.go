@@ -0,0 +1,309 @@
+// Package wildmatch implements git's wildmatch pattern matching, as used for
+// .gitignore patterns and pathspecs. It is a port of git's wildmatch.c and
+// follows its semantics, including POSIX character classes ([[:alpha:]]),
+// backslash escapes inside brackets and the special meaning of "**".
+package wildmatch
+
+import "strings"
+
+type Flags uint
+
+const (
+	// Pathname makes wildcards stop at slashes. Only "**" between slashes, or at
+	// the start or end of the pattern, matches across directories.
+	Pathname Flags = 1 << iota
+	// CaseFold matches ASCII letters case-insensitively.
+	CaseFold
+)
+
+type result int
+
+const (
+	matched result = iota
+	noMatch
+	abortAll
+	abortToStarStar
+)
+
+// Match reports whether text matches pattern.
+func Match(pattern, text string, flags Flags) bool {
+	return dowild(pattern, text, flags) == matched
+}
+
+// at returns s[i], or 0 past the end of s, mirroring C string access.
+func at(s string, i int) byte {
+	if i < len(s) {
+		return s[i]
+	}
+	return 0
+}
+
+func dowild(p, text string, flags Flags) result {
+	var pi, ti int
+	for ; pi < len(p); ti, pi = ti+1, pi+1 {
+		pCh := p[pi]
+		tCh := at(text, ti)
+		if tCh == 0 && pCh != '*' {
+			return abortAll
+		}
+		if flags&CaseFold != 0 {
+			tCh = toLower(tCh)
+			pCh = toLower(pCh)
+		}
+
+		switch pCh {
+		case '\\':
+			// Literal match with following character, a trailing backslash
+			// matches nothing.
+			pi++
+			pCh = at(p, pi)
+			if tCh != pCh {
+				return noMatch
+			}
+		case '?':
+			// Match anything but '/'.
+			if flags&Pathname != 0 && tCh == '/' {
+				return noMatch
+			}
+		case '*':
+			var matchSlash bool
+			pi++
+			if at(p, pi) == '*' {
+				prev := pi - 2
+				for pi++; at(p, pi) == '*'; pi++ {
+				}
+				if flags&Pathname == 0 {
+					// Without Pathname, '*' == '**'.
+					matchSlash = true
+				} else if (prev < 0 || p[prev] == '/') &&
+					(pi == len(p) || p[pi] == '/' || (p[pi] == '\\' && at(p, pi+1) == '/')) {
+					// Assuming "foo/" is already matched and we are at "**/",
+					// first try to match nothing so that "foo/**/bar" matches
+					// both "foo/bar" and "foo/a/bar".
+					if at(p, pi) == '/' && dowild(p[pi+1:], text[ti:], flags) == matched {
+						return matched
+					}
+					matchSlash = true
+				} else {
+					matchSlash = false
+				}
+			} else {
+				// Without Pathname, '*' == '**'.
+				matchSlash = flags&Pathname == 0
+			}
+
+			if pi == len(p) {
+				// A trailing "**" matches everything, a trailing "*" only if
+				// there are no more slashes.
+				if !matchSlash && strings.IndexByte(text[ti:], '/') >= 0 {
+					return noMatch
+				}
+				return matched
+			} else if !matchSlash && p[pi] == '/' {
+				// A single asterisk followed by a slash matches the next
+				// directory.
+				slash := strings.IndexByte(text[ti:], '/')
+				if slash < 0 {
+					return noMatch
+				}
+				// The slash itself is consumed by the loop.
+				ti += slash
+				continue
+			}
+
+			for {
+				if tCh == 0 {
+					break
+				}
+				// Advance faster when the asterisk is followed by a literal,
+				// the text before it must belong to the asterisk. Without
+				// matchSlash do not look past the next slash.
+				if !isGlobSpecial(p[pi]) {
+					pCh = p[pi]
+					if flags&CaseFold != 0 {
+						pCh = toLower(pCh)
+					}
+					for tCh = at(text, ti); tCh != 0 && (matchSlash || tCh != '/'); tCh = at(text, ti) {
+						if flags&CaseFold != 0 {
+							tCh = toLower(tCh)
+						}
+						if tCh == pCh {
+							break
+						}
+						ti++
+					}
+					if tCh != pCh {
+						return noMatch
+					}
+				}
+				if res := dowild(p[pi:], text[ti:], flags); res != noMatch {
+					if !matchSlash || res != abortToStarStar {
+						return res
+					}
+				} else if !matchSlash && tCh == '/' {
+					return abortToStarStar
+				}
+				ti++
+				tCh = at(text, ti)
+				if flags&CaseFold != 0 {
+					tCh = toLower(tCh)
+				}
+			}
+			return abortAll
+		case '[':
+			var res result
+			if pi, res = matchClass(p, pi, tCh, flags); res != matched {
+				return res
+			}
+		default:
+			if tCh != pCh {
+				return noMatch
+			}
+		}
+	}
+
+	if ti < len(text) {
+		return noMatch
+	}
+	return matched
+}
+
+// matchClass matches tCh against the bracket expression starting at p[pi] and
+// returns the index of its closing bracket.
+func matchClass(p string, pi int, tCh byte, flags Flags) (int, result) {
+	pi++
+	pCh := at(p, pi)
+	if pCh == '^' {
+		pCh = '!'
+	}
+	negated := pCh == '!'
+	if negated {
+		pi++
+		pCh = at(p, pi)
+	}
+
+	var prevCh byte
+	found := false
+	for {
+		if pCh == 0 {
+			return pi, abortAll
+		}
+		switch {
+		case pCh == '\\':
+			pi++
+			pCh = at(p, pi)
+			if pCh == 0 {
+				return pi, abortAll
+			}
+			if tCh == pCh {
+				found = true
+			}
+		case pCh == '-' && prevCh != 0 && at(p, pi+1) != 0 && at(p, pi+1) != ']':
+			pi++
+			pCh = p[pi]
+			if pCh == '\\' {
+				pi++
+				pCh = at(p, pi)
+				if pCh == 0 {
+					return pi, abortAll
+				}
+			}
+			if tCh <= pCh && tCh >= prevCh {
+				found = true
+			} else if flags&CaseFold != 0 && isLower(tCh) {
+				if upper := tCh - 'a' + 'A'; upper <= pCh && upper >= prevCh {
+					found = true
+				}
+			}
+			pCh = 0 // resets prevCh
+		case pCh == '[' && at(p, pi+1) == ':':
+			start := pi + 2
+			end := start
+			for ; at(p, end) != 0 && p[end] != ']'; end++ {
+			}
+			if at(p, end) == 0 {
+				return end, abortAll
+			}
+			if end-start-1 < 0 || p[end-1] != ':' {
+				// No ":]", treat it like a normal set.
+				pCh = '['
+				if tCh == pCh {
+					found = true
+				}
+				break
+			}
+			inClass, ok := matchNamedClass(p[start:end-1], tCh, flags)
+			if !ok {
+				// Malformed [:class:] string.
+				return end, abortAll
+			}
+			if inClass {
+				found = true
+			}
+			pi = end
+			pCh = 0 // resets prevCh
+		default:
+			if tCh == pCh {
+				found = true
+			}
+		}
+
+		prevCh = pCh
+		pi++
+		if pCh = at(p, pi); pCh == ']' {
+			break
+		}
+	}
+
+	if found == negated || (flags&Pathname != 0 && tCh == '/') {
+		return pi, noMatch
+	}
+	return pi, matched
+}
+
+func matchNamedClass(class string, c byte, flags Flags) (inClass, ok bool) {
+	switch class {
+	case "alnum":
+		return isAlpha(c) || isDigit(c), true
+	case "alpha":
+		return isAlpha(c), true
+	case "blank":
+		return c == ' ' || c == '\t', true
+	case "cntrl":
+		return c < 0x20 || c == 0x7f, true
+	case "digit":
+		return isDigit(c), true
+	case "graph":
+		return c > ' ' && c < 0x7f, true
+	case "lower":
+		return isLower(c), true
+	case "print":
+		return c >= ' ' && c < 0x7f, true
+	case "punct":
+		return c > ' ' && c < 0x7f && !isAlpha(c) && !isDigit(c), true
+	case "space":
+		return c == ' ' || c == '\t' || c == '\n' || c == '\v' || c == '\f' || c == '\r', true
+	case "upper":
+		return isUpper(c) || (flags&CaseFold != 0 && isLower(c)), true
+	case "xdigit":
+		return isDigit(c) || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F'), true
+	default:
+		return false, false
+	}
+}
+
+func isGlobSpecial(c byte) bool {
+	return c == '*' || c == '?' || c == '[' || c == '\\'
+}
+
+func isDigit(c byte) bool { return c >= '0' && c <= '9' }
+func isLower(c byte) bool { return c >= 'a' && c <= 'z' }
+func isUpper(c byte) bool { return c >= 'A' && c <= 'Z' }
+func isAlpha(c byte) bool { return isLower(c) || isUpper(c) }
+
+func toLower(c byte) byte {
+	if isUpper(c) {
+		return c + 'a' - 'A'
+	}
+	return c
+}
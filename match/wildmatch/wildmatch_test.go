@@ -0,0 +1,103 @@
+package wildmatch
+
+import (
+	_ "embed"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+//go:embed testdata/t3070-wildmatch.txt
+var gitVectors string
+
+func TestGitVectors(t *testing.T) {
+	modes := []struct {
+		name  string
+		flags Flags
+	}{
+		{name: "wildmatch", flags: Pathname},
+		{name: "iwildmatch", flags: Pathname | CaseFold},
+		{name: "pathmatch", flags: 0},
+		{name: "ipathmatch", flags: CaseFold},
+	}
+
+	var n int
+	for lineNo, fields := range parseVectors(t, gitVectors) {
+		require.Equal(t, "match", fields[0], "line %d", lineNo)
+		args := fields[1:]
+		// A continued line carries a second set of expectations for pathspecs.
+		if len(args) == 10 {
+			args = append(args[:4], args[8:]...)
+		}
+		require.Len(t, args, 6, "line %d", lineNo)
+		text, pattern := args[4], args[5]
+		for i, mode := range modes {
+			want := args[i] == "1"
+			require.Equal(t, want, Match(pattern, text, mode.flags),
+				"line %d: %s %q %q", lineNo, mode.name, text, pattern)
+		}
+		n++
+	}
+	require.NotZero(t, n)
+}
+
+// TestSpaceClass covers the whitespace bytes the git vectors can't spell.
+func TestSpaceClass(t *testing.T) {
+	for _, text := range []string{"\t", "\n", "\v", "\f", "\r", " "} {
+		require.True(t, Match("a[[:space:]]b", "a"+text+"b", Pathname), "%q", text)
+	}
+	require.False(t, Match("a[[:space:]]b", "a\x00b", Pathname))
+}
+
+// parseVectors splits the test vectors into shell-like words by line number,
+// joining backslash continued lines and skipping comments.
+func parseVectors(t *testing.T, data string) map[int][]string {
+	t.Helper()
+	res := make(map[int][]string)
+	lines := strings.Split(data, "\n")
+	for i := 0; i < len(lines); i++ {
+		lineNo := i + 1
+		line := strings.TrimSpace(lines[i])
+		for strings.HasSuffix(line, `\`) && !strings.HasSuffix(line, `'\'`) && i+1 < len(lines) {
+			i++
+			line = strings.TrimSuffix(line, `\`) + " " + strings.TrimSpace(lines[i])
+		}
+		if line == "" || line[0] == '#' {
+			continue
+		}
+
+		var (
+			fields []string
+			word   strings.Builder
+			inWord bool
+			quote  byte
+		)
+		for j := 0; j < len(line); j++ {
+			c := line[j]
+			switch {
+			case quote != 0 && c == quote:
+				quote = 0
+			case quote != 0:
+				word.WriteByte(c)
+			case c == '\'' || c == '"':
+				quote, inWord = c, true
+			case c == ' ' || c == '\t':
+				if inWord {
+					fields = append(fields, word.String())
+					word.Reset()
+					inWord = false
+				}
+			default:
+				word.WriteByte(c)
+				inWord = true
+			}
+		}
+		require.Zero(t, quote, "line %d: unterminated quote", lineNo)
+		if inWord {
+			fields = append(fields, word.String())
+		}
+		res[lineNo] = fields
+	}
+	return res
+}
@@ -3,6 +3,10 @@ package gopathignore
 import (
 	"context"
 	"fmt"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/vbhat161/go-path-ignore/match"
@@ -13,7 +17,14 @@ import (
 
 type PathIgnore struct {
 	matchers []match.PathMatcher
-	timeout  time.Duration
+	// strategies are the matchers of the strategies, which matchers combine into
+	// one in single pass mode.
+	strategies []match.PathMatcher
+	// opts are the options the matchers were built from.
+	opts    Options
+	timeout time.Duration
+	workers int
+	cache   *cache
 }
 
 type Options struct {
@@ -22,6 +33,22 @@ type Options struct {
 	GitIgnore *gitignore.Options
 	Timeout   time.Duration
 	Parallel  bool
+	// SinglePass lowers the rules of every strategy to RE2 and merges them into a
+	// single RE2 set, so that a path is scanned once instead of once per strategy.
+	// Results are the same as in parallel mode, which it implies. Not supported for
+	// gitignore.EngineWildmatch.
+	SinglePass bool
+	// IgnoreCase makes every strategy match case-insensitively, like git's
+	// core.ignoreCase. It is applied on top of the strategy options.
+	IgnoreCase bool
+	// Workers is the number of goroutines MatchAll spreads paths over,
+	// runtime.GOMAXPROCS(0) by default.
+	Workers int
+	// CacheSize, if positive, is the number of match decisions kept in an LRU cache
+	// by path. With a cache, paths below a directory known to be excluded by a
+	// gitignore rule are only matched against the regex rules, and otherwise
+	// reported with that rule.
+	CacheSize int
 }
 
 func New(opts Options) (*PathIgnore, error) {
@@ -32,6 +59,8 @@ func New(opts Options) (*PathIgnore, error) {
 		return nil, fmt.Errorf("atleast one matching strategy required")
 	}
 
+	opts = opts.normalized()
+
 	if opts.Regex != nil {
 		var matcher *regex.Matcher
 		var err error
@@ -74,7 +103,63 @@ func New(opts Options) (*PathIgnore, error) {
 		matchers = append(matchers, matcher)
 	}
 
-	return &PathIgnore{matchers: matchers, timeout: opts.Timeout}, nil
+	return newPathIgnore(opts, matchers)
+}
+
+// newPathIgnore returns a PathIgnore for the matchers built from opts, in the
+// order regex, gitignore, glob.
+func newPathIgnore(opts Options, matchers []match.PathMatcher) (*PathIgnore, error) {
+	strategies := matchers
+	if opts.SinglePass {
+		matcher, err := newSetMatcher(matchers)
+		if err != nil {
+			return nil, fmt.Errorf("single pass - %w", err)
+		}
+		matchers = []match.PathMatcher{matcher}
+	}
+
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	pi := &PathIgnore{matchers: matchers, strategies: strategies, opts: opts, timeout: opts.Timeout, workers: workers}
+	if opts.CacheSize > 0 {
+		pi.cache = newCache(opts.CacheSize)
+	}
+	return pi, nil
+}
+
+// normalized applies the options that imply others.
+func (opts Options) normalized() Options {
+	if opts.IgnoreCase {
+		opts = opts.withIgnoreCase()
+	}
+	if opts.SinglePass {
+		opts.Parallel = true
+	}
+	return opts
+}
+
+// withIgnoreCase returns a copy of the options with case folding enabled for
+// every strategy, leaving the caller's strategy options untouched.
+func (opts Options) withIgnoreCase() Options {
+	if opts.Regex != nil {
+		o := *opts.Regex
+		o.IgnoreCase = true
+		opts.Regex = &o
+	}
+	if opts.GitIgnore != nil {
+		o := *opts.GitIgnore
+		o.IgnoreCase = true
+		opts.GitIgnore = &o
+	}
+	if opts.Glob != nil {
+		o := *opts.Glob
+		o.IgnoreCase = true
+		opts.Glob = &o
+	}
+	return opts
 }
 
 func (pi *PathIgnore) Match(ctx context.Context, path string) (bool, error) {
@@ -83,22 +168,139 @@ func (pi *PathIgnore) Match(ctx context.Context, path string) (bool, error) {
 }
 
 func (pi *PathIgnore) Match2(ctx context.Context, path string) (match.MatchInfo, error) {
-	timeout := pi.timeout
-	if timeout == 0 {
-		timeout = time.Hour // max
+	return pi.cached(cacheKey{path: path, kind: kindPath}, func(matchers []match.PathMatcher) (match.MatchInfo, error) {
+		return pi.match(ctx, matchers, func(ctx context.Context, m match.PathMatcher) (match.MatchInfo, error) {
+			return m.Match2(ctx, path)
+		})
+	})
+}
+
+// MatchEntry is like Match2 but takes whether path names a directory, so that
+// directory-only gitignore patterns such as "build/" skip regular files.
+func (pi *PathIgnore) MatchEntry(ctx context.Context, path string, isDir bool) (match.MatchInfo, error) {
+	return pi.cached(entryKey(path, isDir), func(matchers []match.PathMatcher) (match.MatchInfo, error) {
+		return pi.match(ctx, matchers, func(ctx context.Context, m match.PathMatcher) (match.MatchInfo, error) {
+			return m.MatchEntry(ctx, path, isDir)
+		})
+	})
+}
+
+// MatchAll is like Match2 for a batch of paths, the result for paths[i] is at index
+// i. Paths are matched concurrently by Options.Workers goroutines, and Timeout
+// applies to the batch as a whole. The first error stops the batch.
+func (pi *PathIgnore) MatchAll(ctx context.Context, paths []string) ([]match.MatchInfo, error) {
+	matchCtx, cancel := pi.withTimeout(ctx)
+	defer cancel()
+
+	var (
+		results = make([]match.MatchInfo, len(paths))
+		next    atomic.Int64
+		errOnce sync.Once
+		err     error
+		wg      sync.WaitGroup
+	)
+	for range min(pi.workers, len(paths)) {
+		wg.Go(func() {
+			for {
+				i := int(next.Add(1) - 1)
+				if i >= len(paths) || matchCtx.Err() != nil {
+					return
+				}
+				res, e := pi.cached(cacheKey{path: paths[i], kind: kindPath}, func(matchers []match.PathMatcher) (match.MatchInfo, error) {
+					return pi.first(matchCtx, matchers, func(ctx context.Context, m match.PathMatcher) (match.MatchInfo, error) {
+						return m.Match2(ctx, paths[i])
+					})
+				})
+				if e != nil {
+					errOnce.Do(func() {
+						err = e
+						cancel()
+					})
+					return
+				}
+				results[i] = res
+			}
+		})
 	}
+	wg.Wait()
 
-	matchCtx, cancel := context.WithTimeout(ctx, timeout)
+	if err != nil {
+		return nil, err
+	}
+	if err := matchCtx.Err(); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+func (pi *PathIgnore) match(
+	ctx context.Context,
+	matchers []match.PathMatcher,
+	matchFn func(context.Context, match.PathMatcher) (match.MatchInfo, error),
+) (match.MatchInfo, error) {
+	matchCtx, cancel := pi.withTimeout(ctx)
 	defer cancel()
 
-	for _, matcher := range pi.matchers {
-		if m, err := matcher.Match2(matchCtx, path); err != nil {
+	return pi.first(matchCtx, matchers, matchFn)
+}
+
+// first returns the first positive result of matchers.
+func (pi *PathIgnore) first(
+	ctx context.Context,
+	matchers []match.PathMatcher,
+	matchFn func(context.Context, match.PathMatcher) (match.MatchInfo, error),
+) (match.MatchInfo, error) {
+	for _, matcher := range matchers {
+		if m, err := matchFn(ctx, matcher); err != nil {
 			return nil, err
 		} else if m.Ok() {
-			cancel()
 			return m, nil
 		}
 	}
 
 	return match.NoMatch, nil
 }
+
+// withTimeout returns a context bounded by the configured timeout.
+func (pi *PathIgnore) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	timeout := pi.timeout
+	if timeout == 0 {
+		timeout = time.Hour // max
+	}
+
+	return context.WithTimeout(ctx, timeout)
+}
+
+// Explain reports every rule of every strategy matching path, together with the
+// result Match2 returns for it. A trailing slash marks path as a directory.
+func (pi *PathIgnore) Explain(ctx context.Context, path string) (match.Explanation, error) {
+	res, err := pi.Match2(ctx, path)
+	if err != nil {
+		return match.Explanation{}, err
+	}
+
+	explainCtx, cancel := pi.withTimeout(ctx)
+	defer cancel()
+
+	isDir := strings.HasSuffix(path, "/")
+	var hits []match.Hit
+	for _, matcher := range pi.matchers {
+		h, err := matcher.Explain(explainCtx, path, isDir)
+		if err != nil {
+			return match.Explanation{}, err
+		}
+		hits = append(hits, h...)
+	}
+
+	// Only the strategy that produced the result decides; when nothing matched,
+	// a decisive hit can only be a negation.
+	for i := range hits {
+		if res.Ok() {
+			hits[i].Decisive = hits[i].Decisive && hits[i].Type == res.Type()
+		} else {
+			hits[i].Decisive = hits[i].Decisive && hits[i].Negate
+		}
+	}
+
+	return match.Explanation{Path: path, Hits: hits, Result: res}, nil
+}
@@ -2,10 +2,12 @@ package gopathignore_test
 
 import (
 	"context"
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 	gopathignore "github.com/vbhat161/go-path-ignore"
+	"github.com/vbhat161/go-path-ignore/match"
 	"github.com/vbhat161/go-path-ignore/match/gitignore"
 	"github.com/vbhat161/go-path-ignore/match/glob"
 	"github.com/vbhat161/go-path-ignore/match/regex"
@@ -227,6 +229,26 @@ func TestMatch(t *testing.T) {
 			path: "baz/qux",
 			want: false,
 		},
+		{
+			name: "ignore case across strategies",
+			opts: gopathignore.Options{
+				Regex:      &regex.Options{Patterns: []string{`\.bak$`}},
+				GitIgnore:  &gitignore.Options{Patterns: []string{"build/"}},
+				Glob:       &glob.Options{Patterns: []string{"*.tmp"}},
+				IgnoreCase: true,
+				Parallel:   true,
+			},
+			path: "Build/out.o",
+			want: true,
+		},
+		{
+			name: "case sensitive by default",
+			opts: gopathignore.Options{
+				GitIgnore: &gitignore.Options{Patterns: []string{"build/"}},
+			},
+			path: "Build/out.o",
+			want: false,
+		},
 		{
 			name: "invalid regex pattern during creation",
 			opts: gopathignore.Options{
@@ -273,6 +295,90 @@ func TestMatch(t *testing.T) {
 	}
 }
 
+func TestMatchEntry(t *testing.T) {
+	pi, err := gopathignore.New(gopathignore.Options{
+		GitIgnore: &gitignore.Options{Patterns: []string{"build/"}},
+		Glob:      &glob.Options{Patterns: []string{"*.tmp"}},
+	})
+	require.NoError(t, err)
+
+	res, err := pi.MatchEntry(context.Background(), "build", false)
+	require.NoError(t, err)
+	require.False(t, res.Ok())
+
+	res, err = pi.MatchEntry(context.Background(), "build", true)
+	require.NoError(t, err)
+	require.True(t, res.Ok())
+	require.Equal(t, "build/", res.Src())
+
+	res, err = pi.MatchEntry(context.Background(), "cache.tmp", true)
+	require.NoError(t, err)
+	require.True(t, res.Ok())
+}
+
+func TestExplain(t *testing.T) {
+	pi, err := gopathignore.New(gopathignore.Options{
+		Regex:     &regex.Options{Patterns: []string{`\.tmp$`}},
+		GitIgnore: &gitignore.Options{Patterns: []string{"*.tmp", "!keep.tmp"}},
+		Glob:      &glob.Options{Patterns: []string{"*.tmp"}},
+	})
+	require.NoError(t, err)
+
+	exp, err := pi.Explain(context.Background(), "keep.tmp")
+	require.NoError(t, err)
+	require.Equal(t, "keep.tmp", exp.Path)
+	require.True(t, exp.Result.Ok())
+	require.Equal(t, match.Regex, exp.Result.Type())
+	require.Equal(t, []match.Hit{
+		{Type: match.Regex, Source: match.Source{Pattern: `\.tmp$`, Line: 1}, Path: "keep.tmp", Decisive: true},
+		{Type: match.GitIgnore, Source: match.Source{Pattern: "*.tmp", Line: 1}, Path: "keep.tmp"},
+		{Type: match.GitIgnore, Source: match.Source{Pattern: "!keep.tmp", Line: 2, Negate: true}, Path: "keep.tmp"},
+		{Type: match.Glob, Source: match.Source{Pattern: "*.tmp", Line: 1}, Path: "keep.tmp"},
+	}, exp.Hits)
+
+	exp, err = pi.Explain(context.Background(), "main.go")
+	require.NoError(t, err)
+	require.False(t, exp.Result.Ok())
+	require.Empty(t, exp.Hits)
+}
+
+func TestMatchAll(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	for _, workers := range []int{0, 1, 3} {
+		pi, err := gopathignore.New(gopathignore.Options{
+			GitIgnore: &gitignore.Options{Patterns: []string{"*.log", "!keep.log", "build/"}},
+			Glob:      &glob.Options{Patterns: []string{"*.tmp"}},
+			Workers:   workers,
+		})
+		require.NoError(t, err)
+
+		var paths []string
+		for i := range 1000 {
+			paths = append(paths, fmt.Sprintf("dir-%d/file.%s", i, []string{"log", "tmp", "go", "keep.log"}[i%4]))
+		}
+		paths = append(paths, "build/out.o", "keep.log")
+
+		results, err := pi.MatchAll(context.Background(), paths)
+		require.NoError(t, err)
+		require.Len(t, results, len(paths))
+		for i, path := range paths {
+			want, err := pi.Match2(context.Background(), path)
+			require.NoError(t, err)
+			require.Equal(t, want, results[i], path)
+		}
+
+		results, err = pi.MatchAll(context.Background(), nil)
+		require.NoError(t, err)
+		require.Empty(t, results)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err = pi.MatchAll(ctx, paths)
+		require.ErrorIs(t, err, context.Canceled)
+	}
+}
+
 func Benchmark(b *testing.B) {
 	bench := func(parallel bool) func(*testing.B) {
 		return func(bench *testing.B) {
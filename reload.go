@@ -0,0 +1,259 @@
+package gopathignore
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/vbhat161/go-path-ignore/match"
+	"github.com/vbhat161/go-path-ignore/match/gitignore"
+)
+
+// ReloadEvent reports a reload of the rules of a Reloader.
+type ReloadEvent struct {
+	// Changed lists the source files and directories that were modified, created
+	// or removed. It is empty for reloads requested with Reload.
+	Changed []string
+	// Err is set if the rules could not be rebuilt, the previous rules then stay
+	// in use.
+	Err error
+}
+
+type ReloadOptions struct {
+	// Interval is how often the source files are checked for changes, 2 seconds by
+	// default.
+	Interval time.Duration
+	// OnEvent, if set, is called after every reload. It is called from the polling
+	// goroutine, or from Reload, and must not block for long.
+	OnEvent func(ReloadEvent)
+}
+
+// Reloader is a PathIgnore that rebuilds its rules when the gitignore files they
+// were loaded from change. Files are polled, rebuilt rules are swapped in
+// atomically, so matching is safe while a reload is in progress.
+type Reloader struct {
+	opts    Options
+	onEvent func(ReloadEvent)
+	current atomic.Pointer[PathIgnore]
+
+	mu     sync.Mutex // serializes reloads
+	stamps map[string]fileStamp
+
+	stop     chan struct{}
+	stopOnce sync.Once
+	done     chan struct{}
+}
+
+// fileStamp identifies a version of a file, a zero fileStamp a missing file. A
+// directory is identified by the entries that can bring in ignore files, see
+// dirEntries, rather than by its modification time, which changes whenever a file
+// is created or saved in it.
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+	dir     bool
+	entries string
+}
+
+// NewReloader builds a PathIgnore from opts and starts polling its gitignore files
+// for changes. Close stops it. Gitignore patterns read from Options.Reader cannot
+// be reloaded and are rejected.
+func NewReloader(opts Options, ropts ReloadOptions) (*Reloader, error) {
+	if opts.GitIgnore != nil && opts.GitIgnore.Reader != nil {
+		return nil, fmt.Errorf("gitignore reader cannot be reloaded")
+	}
+
+	pi, err := New(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	interval := ropts.Interval
+	if interval == 0 {
+		interval = 2 * time.Second
+	}
+
+	r := &Reloader{
+		opts:    opts,
+		onEvent: ropts.OnEvent,
+		stamps:  map[string]fileStamp{},
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	r.current.Store(pi)
+	for _, f := range pi.files() {
+		r.stamps[f] = r.stat(f)
+	}
+
+	go r.poll(interval)
+	return r, nil
+}
+
+// Current returns the rules in use.
+func (r *Reloader) Current() *PathIgnore {
+	return r.current.Load()
+}
+
+func (r *Reloader) Match(ctx context.Context, path string) (bool, error) {
+	return r.Current().Match(ctx, path)
+}
+
+func (r *Reloader) Match2(ctx context.Context, path string) (match.MatchInfo, error) {
+	return r.Current().Match2(ctx, path)
+}
+
+func (r *Reloader) MatchEntry(ctx context.Context, path string, isDir bool) (match.MatchInfo, error) {
+	return r.Current().MatchEntry(ctx, path, isDir)
+}
+
+func (r *Reloader) Explain(ctx context.Context, path string) (match.Explanation, error) {
+	return r.Current().Explain(ctx, path)
+}
+
+// Reload rebuilds the rules now, whether the source files changed or not.
+func (r *Reloader) Reload() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.reload(nil)
+}
+
+// Close stops polling the source files. The current rules remain usable.
+func (r *Reloader) Close() error {
+	r.stopOnce.Do(func() { close(r.stop) })
+	<-r.done
+	return nil
+}
+
+func (r *Reloader) poll(interval time.Duration) {
+	defer close(r.done)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-r.stop:
+			return
+		case <-ticker.C:
+			r.check()
+		}
+	}
+}
+
+// check reloads the rules if a source file changed since the last check.
+func (r *Reloader) check() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var changed []string
+	for f, old := range r.stamps {
+		if stamp := r.stat(f); stamp != old {
+			changed = append(changed, f)
+		}
+	}
+	if len(changed) > 0 {
+		slices.Sort(changed)
+		_ = r.reload(changed) // reported to OnEvent
+	}
+}
+
+// reload rebuilds the rules. The files are stamped before they are read, so that
+// changes made during the rebuild are picked up by the next check. A failed
+// rebuild is not retried until the files change again.
+func (r *Reloader) reload(changed []string) error {
+	before := make(map[string]fileStamp, len(r.stamps))
+	for f := range r.stamps {
+		before[f] = r.stat(f)
+	}
+
+	pi, err := New(r.opts)
+	files := slices.Collect(maps.Keys(before))
+	if err == nil {
+		files = pi.files()
+		r.current.Store(pi)
+	}
+
+	r.stamps = make(map[string]fileStamp, len(files))
+	for _, f := range files {
+		if stamp, ok := before[f]; ok {
+			r.stamps[f] = stamp
+		} else {
+			r.stamps[f] = r.stat(f)
+		}
+	}
+
+	if r.onEvent != nil {
+		r.onEvent(ReloadEvent{Changed: changed, Err: err})
+	}
+	return err
+}
+
+func (r *Reloader) stat(name string) fileStamp {
+	return stat(r.opts, name)
+}
+
+// stat returns the stamp of a gitignore source file or directory of opts.
+func stat(opts Options, name string) fileStamp {
+	var fsys fs.FS
+	if opts.GitIgnore != nil && opts.GitIgnore.FS != nil {
+		fsys = opts.GitIgnore.FS
+	}
+	var info fs.FileInfo
+	var err error
+	if fsys != nil {
+		info, err = fs.Stat(fsys, name)
+	} else {
+		info, err = os.Stat(name)
+	}
+	if err != nil {
+		return fileStamp{}
+	}
+	if info.IsDir() {
+		return fileStamp{dir: true, entries: dirEntries(fsys, name)}
+	}
+	return fileStamp{modTime: info.ModTime(), size: info.Size()}
+}
+
+// dirEntries lists the entries of a directory that the rules depend on: its
+// ignore file, if any, and its subdirectories, which may hold ignore files of
+// their own. Files coming and going do not change it.
+func dirEntries(fsys fs.FS, name string) string {
+	var entries []fs.DirEntry
+	var err error
+	if fsys != nil {
+		entries, err = fs.ReadDir(fsys, name)
+	} else {
+		entries, err = os.ReadDir(name)
+	}
+	if err != nil {
+		return ""
+	}
+
+	var sb strings.Builder
+	for _, e := range entries {
+		switch {
+		case e.IsDir() && e.Name() != ".git":
+			sb.WriteString(e.Name() + "/\x00")
+		case !e.IsDir() && e.Name() == gitignore.FileName:
+			sb.WriteString(e.Name() + "\x00")
+		}
+	}
+	return sb.String()
+}
+
+// files returns the files the gitignore rules were loaded from.
+func (pi *PathIgnore) files() []string {
+	for _, m := range pi.strategies {
+		if gi, ok := m.(*gitignore.Matcher); ok {
+			return gi.Files()
+		}
+	}
+	return nil
+}
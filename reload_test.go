@@ -0,0 +1,113 @@
+package gopathignore_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	gopathignore "github.com/vbhat161/go-path-ignore"
+	"github.com/vbhat161/go-path-ignore/match/gitignore"
+	"go.uber.org/goleak"
+)
+
+func TestReloader(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	root := t.TempDir()
+	gitIgnorePath := filepath.Join(root, ".gitignore")
+	require.NoError(t, os.WriteFile(gitIgnorePath, []byte("*.tmp\n"), 0o600))
+
+	events := make(chan gopathignore.ReloadEvent, 10)
+	r, err := gopathignore.NewReloader(
+		gopathignore.Options{GitIgnore: &gitignore.Options{Root: root}},
+		gopathignore.ReloadOptions{
+			Interval: 10 * time.Millisecond,
+			OnEvent:  func(e gopathignore.ReloadEvent) { events <- e },
+		},
+	)
+	require.NoError(t, err)
+	defer r.Close()
+
+	requireMatch := func(path string, want bool) {
+		t.Helper()
+		ok, err := r.Match(context.Background(), path)
+		require.NoError(t, err)
+		require.Equal(t, want, ok, path)
+	}
+	nextEvent := func() gopathignore.ReloadEvent {
+		t.Helper()
+		select {
+		case e := <-events:
+			return e
+		case <-time.After(5 * time.Second):
+			t.Fatal("no reload event")
+			return gopathignore.ReloadEvent{}
+		}
+	}
+
+	requireMatch("a.tmp", true)
+	requireMatch("a.log", false)
+
+	// Files created, saved atomically or removed next to the ignore files do not
+	// change the rules.
+	require.NoError(t, os.WriteFile(filepath.Join(root, "main.go.tmp"), []byte("package main\n"), 0o600))
+	require.NoError(t, os.Rename(filepath.Join(root, "main.go.tmp"), filepath.Join(root, "main.go")))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "a.txt"), nil, 0o600))
+	require.NoError(t, os.Remove(filepath.Join(root, "a.txt")))
+	select {
+	case e := <-events:
+		t.Fatalf("unexpected reload: %+v", e)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	// An edited ignore file.
+	require.NoError(t, os.WriteFile(gitIgnorePath, []byte("*.tmp\n*.log\n"), 0o600))
+	e := nextEvent()
+	require.NoError(t, e.Err)
+	require.Equal(t, []string{gitIgnorePath}, e.Changed)
+	requireMatch("a.log", true)
+
+	// A new ignore file in a new directory.
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "sub"), 0o700))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "sub", ".gitignore"), []byte("*.txt\n"), 0o600))
+	require.Eventually(t, func() bool {
+		ok, err := r.Match(context.Background(), "sub/a.txt")
+		return err == nil && ok
+	}, 5*time.Second, 10*time.Millisecond)
+	requireMatch("a.txt", false)
+	for len(events) > 0 {
+		<-events
+	}
+
+	// A broken ignore file keeps the previous rules.
+	require.NoError(t, os.WriteFile(gitIgnorePath, []byte("a[\n"), 0o600))
+	e = nextEvent()
+	require.Error(t, e.Err)
+	requireMatch("a.log", true)
+
+	require.NoError(t, os.WriteFile(gitIgnorePath, []byte("*.md\n"), 0o600))
+	e = nextEvent()
+	require.NoError(t, e.Err)
+	requireMatch("a.log", false)
+	requireMatch("a.md", true)
+
+	require.NoError(t, r.Reload())
+	e = nextEvent()
+	require.NoError(t, e.Err)
+	require.Empty(t, e.Changed)
+
+	require.NoError(t, r.Close())
+	requireMatch("a.md", true)
+}
+
+func TestReloaderReader(t *testing.T) {
+	_, err := gopathignore.NewReloader(
+		gopathignore.Options{GitIgnore: &gitignore.Options{Reader: strings.NewReader("*.log")}},
+		gopathignore.ReloadOptions{},
+	)
+	require.Error(t, err)
+}
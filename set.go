@@ -0,0 +1,122 @@
+package gopathignore
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/vbhat161/go-path-ignore/match"
+)
+
+var _ match.PathMatcher = (*setMatcher)(nil)
+
+// setMatcher evaluates the rules of several matchers with a single RE2 set. Each
+// matcher decides in turn from the indices of its own expressions, the first
+// positive result wins as for PathIgnore.
+type setMatcher struct {
+	matchers []match.SetMatcher
+	// offsets are the indices of the first expression of each matcher in the set.
+	offsets []int
+	set     *match.RE2Set
+}
+
+func newSetMatcher(matchers []match.PathMatcher) (*setMatcher, error) {
+	sm := &setMatcher{}
+	var exprs []string
+	for _, m := range matchers {
+		s, ok := m.(match.SetMatcher)
+		if !ok {
+			return nil, fmt.Errorf("%s rules cannot be lowered to RE2", m.Type())
+		}
+		e, err := s.Exprs()
+		if err != nil {
+			return nil, fmt.Errorf("%s - %w", m.Type(), err)
+		}
+		sm.matchers = append(sm.matchers, s)
+		sm.offsets = append(sm.offsets, len(exprs))
+		exprs = append(exprs, e...)
+	}
+	sm.offsets = append(sm.offsets, len(exprs))
+
+	if len(exprs) == 0 {
+		return sm, nil
+	}
+	set, err := match.NewRE2Set(exprs)
+	if err != nil {
+		return nil, fmt.Errorf("single set - %w", err)
+	}
+	sm.set = set
+	return sm, nil
+}
+
+// Type is Unknown, results report the strategy that matched.
+func (sm *setMatcher) Type() match.Type {
+	return match.Unknown
+}
+
+func (sm *setMatcher) Match(ctx context.Context, path string) (bool, error) {
+	res, err := sm.Match2(ctx, path)
+	return res.Ok(), err
+}
+
+func (sm *setMatcher) Match2(ctx context.Context, path string) (match.MatchInfo, error) {
+	return sm.match(func(m match.SetMatcher, hits func(string) []int) (match.MatchInfo, error) {
+		return m.MatchSet(ctx, path, strings.HasSuffix(path, "/"), hits)
+	})
+}
+
+func (sm *setMatcher) MatchEntry(ctx context.Context, path string, isDir bool) (match.MatchInfo, error) {
+	return sm.match(func(m match.SetMatcher, hits func(string) []int) (match.MatchInfo, error) {
+		return m.MatchSet(ctx, path, isDir, hits)
+	})
+}
+
+// match scans every path the matchers ask about once, the whole path and, for
+// gitignore rules, its parent directories, and hands each matcher the indices of
+// its own expressions.
+func (sm *setMatcher) match(matchFn func(match.SetMatcher, func(string) []int) (match.MatchInfo, error)) (match.MatchInfo, error) {
+	scanned := make(map[string][]int, 1)
+	scan := func(path string) []int {
+		if idx, ok := scanned[path]; ok {
+			return idx
+		}
+		var idx []int
+		if sm.set != nil {
+			idx = sm.set.MatchIndices(path)
+		}
+		scanned[path] = idx
+		return idx
+	}
+
+	for i, m := range sm.matchers {
+		lo, hi := sm.offsets[i], sm.offsets[i+1]
+		hits := func(path string) []int {
+			var own []int
+			for _, j := range scan(path) {
+				if j >= lo && j < hi {
+					own = append(own, j-lo)
+				}
+			}
+			return own
+		}
+		if res, err := matchFn(m, hits); err != nil {
+			return nil, err
+		} else if res.Ok() {
+			return res, nil
+		}
+	}
+	return match.NoMatch, nil
+}
+
+// Explain asks every matcher in turn, which evaluate their rules on their own.
+func (sm *setMatcher) Explain(ctx context.Context, path string, isDir bool) ([]match.Hit, error) {
+	var hits []match.Hit
+	for _, m := range sm.matchers {
+		h, err := m.Explain(ctx, path, isDir)
+		if err != nil {
+			return nil, err
+		}
+		hits = append(hits, h...)
+	}
+	return hits, nil
+}
@@ -0,0 +1,101 @@
+package gopathignore_test
+
+import (
+	"context"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/require"
+	gopathignore "github.com/vbhat161/go-path-ignore"
+	"github.com/vbhat161/go-path-ignore/match"
+	"github.com/vbhat161/go-path-ignore/match/gitignore"
+	"github.com/vbhat161/go-path-ignore/match/glob"
+	"github.com/vbhat161/go-path-ignore/match/regex"
+)
+
+func TestSinglePass(t *testing.T) {
+	fsys := fstest.MapFS{
+		".gitignore":     {Data: []byte("*.log\n!keep.log\n/build/\n")},
+		"src/.gitignore": {Data: []byte("gen/\n!debug.log\n")},
+		"src/main.go":    {},
+	}
+
+	for _, ignoreCase := range []bool{false, true} {
+		opts := func(singlePass bool) gopathignore.Options {
+			return gopathignore.Options{
+				Regex:      &regex.Options{Patterns: []string{`\.bak$`, `^tmp/`}},
+				GitIgnore:  &gitignore.Options{Patterns: []string{"vendor/"}, FS: fsys, Root: "."},
+				Glob:       &glob.Options{Patterns: []string{"*.{tmp,swp}", "docs/**/draft?.md"}, RawPatterns: []string{"[x].txt"}},
+				IgnoreCase: ignoreCase,
+				SinglePass: singlePass,
+			}
+		}
+		want, err := gopathignore.New(opts(false))
+		require.NoError(t, err)
+		got, err := gopathignore.New(opts(true))
+		require.NoError(t, err)
+
+		for _, path := range []string{
+			"main.go", "a.bak", "tmp/x", "debug.log", "keep.log", "Keep.LOG", "build/", "build",
+			"build/keep.log", "src/debug.log", "src/gen/", "src/gen/a.go", "gen/a.go", "vendor/x",
+			"src/vendor/x", "a.swp", "A.TMP", "docs/x/draft1.md", "docs/draft12.md", "[x].txt", "x.txt",
+		} {
+			wantRes, err := want.Match2(context.Background(), path)
+			require.NoError(t, err)
+			gotRes, err := got.Match2(context.Background(), path)
+			require.NoError(t, err)
+			require.Equal(t, wantRes.Ok(), gotRes.Ok(), "ignoreCase %v, path %q", ignoreCase, path)
+			require.Equal(t, wantRes.Type(), gotRes.Type(), path)
+			require.Equal(t, wantRes.Source(), gotRes.Source(), path)
+
+			for _, isDir := range []bool{false, true} {
+				wantRes, err := want.MatchEntry(context.Background(), path, isDir)
+				require.NoError(t, err)
+				gotRes, err := got.MatchEntry(context.Background(), path, isDir)
+				require.NoError(t, err)
+				require.Equal(t, wantRes.Source(), gotRes.Source(), "path %q, isDir %v", path, isDir)
+			}
+		}
+	}
+}
+
+func TestSinglePassMatchInfo(t *testing.T) {
+	pi, err := gopathignore.New(gopathignore.Options{
+		Regex:      &regex.Options{Patterns: []string{`\.tmp$`}},
+		GitIgnore:  &gitignore.Options{Patterns: []string{"*.log", "!keep.log"}},
+		Glob:       &glob.Options{Patterns: []string{"*.log"}},
+		SinglePass: true,
+	})
+	require.NoError(t, err)
+
+	res, err := pi.Match2(context.Background(), "a.tmp")
+	require.NoError(t, err)
+	require.Equal(t, match.Regex, res.Type())
+	require.Equal(t, match.Source{Pattern: `\.tmp$`, Line: 1}, res.Source())
+
+	res, err = pi.Match2(context.Background(), "debug.log")
+	require.NoError(t, err)
+	require.Equal(t, match.GitIgnore, res.Type())
+	require.Equal(t, match.Source{Pattern: "*.log", Line: 1}, res.Source())
+
+	res, err = pi.Match2(context.Background(), "keep.log")
+	require.NoError(t, err)
+	require.Equal(t, match.Glob, res.Type())
+	require.Equal(t, match.Source{Pattern: "*.log", Line: 1}, res.Source())
+
+	exp, err := pi.Explain(context.Background(), "keep.log")
+	require.NoError(t, err)
+	require.Equal(t, []match.Hit{
+		{Type: match.GitIgnore, Source: match.Source{Pattern: "*.log", Line: 1}, Path: "keep.log"},
+		{Type: match.GitIgnore, Source: match.Source{Pattern: "!keep.log", Line: 2, Negate: true}, Path: "keep.log"},
+		{Type: match.Glob, Source: match.Source{Pattern: "*.log", Line: 1}, Path: "keep.log", Decisive: true},
+	}, exp.Hits)
+}
+
+func TestSinglePassWildmatch(t *testing.T) {
+	_, err := gopathignore.New(gopathignore.Options{
+		GitIgnore:  &gitignore.Options{Patterns: []string{"*.log"}, Engine: gitignore.EngineWildmatch},
+		SinglePass: true,
+	})
+	require.Error(t, err)
+}
@@ -0,0 +1,307 @@
+package gopathignore
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/vbhat161/go-path-ignore/match"
+	"github.com/vbhat161/go-path-ignore/match/gitignore"
+	"github.com/vbhat161/go-path-ignore/match/glob"
+	"github.com/vbhat161/go-path-ignore/match/regex"
+)
+
+// SnapshotVersion is the version of the snapshot format written by WriteSnapshot.
+// Snapshots of other versions are rejected.
+const SnapshotVersion = 1
+
+var (
+	// ErrSnapshotVersion is returned for snapshots of another SnapshotVersion.
+	ErrSnapshotVersion = errors.New("unsupported snapshot version")
+	// ErrSnapshotCorrupt is returned for truncated or modified snapshots.
+	ErrSnapshotCorrupt = errors.New("corrupt snapshot")
+	// ErrSnapshotStale is returned for snapshots taken with other options, or
+	// whose gitignore source files changed content since.
+	ErrSnapshotStale = errors.New("stale snapshot")
+)
+
+// snapshotMagic starts every snapshot. It is followed by the version as a big
+// endian uint32, the gob encoded snapshot and its SHA-256 checksum.
+var snapshotMagic = []byte("GPIS")
+
+// snapshot is the content of a snapshot: the parsed rules of every strategy,
+// along with what they were built from.
+type snapshot struct {
+	// Options is the fingerprint of the options, see fingerprint.
+	Options []byte
+	// Files are the stamps of the gitignore source files by name.
+	Files map[string]snapshotStamp
+
+	Regex     *regex.State
+	GitIgnore *gitignore.State
+	Glob      *glob.State
+}
+
+// snapshotStamp identifies a version of a gitignore source file by its size and
+// SHA-256 content hash, or of a directory like fileStamp does. It is zero for a
+// missing file.
+type snapshotStamp struct {
+	Size    int64
+	Hash    []byte
+	Dir     bool
+	Entries string
+}
+
+// newSnapshotStamp returns the stamp of a gitignore source file or directory of
+// opts.
+func newSnapshotStamp(opts Options, name string) snapshotStamp {
+	s := stat(opts, name)
+	if s == (fileStamp{}) {
+		return snapshotStamp{}
+	}
+	if s.dir {
+		return snapshotStamp{Dir: true, Entries: s.entries}
+	}
+
+	var data []byte
+	var err error
+	if opts.GitIgnore != nil && opts.GitIgnore.FS != nil {
+		data, err = fs.ReadFile(opts.GitIgnore.FS, name)
+	} else {
+		data, err = os.ReadFile(name)
+	}
+	if err != nil {
+		return snapshotStamp{}
+	}
+	sum := sha256.Sum256(data)
+	return snapshotStamp{Size: int64(len(data)), Hash: sum[:]}
+}
+
+func (s snapshotStamp) equal(o snapshotStamp) bool {
+	return s.Size == o.Size && bytes.Equal(s.Hash, o.Hash) && s.Dir == o.Dir && s.Entries == o.Entries
+}
+
+// WriteSnapshot writes the parsed rules of pi to w, so that LoadSnapshot can
+// rebuild it without reading and parsing the ignore sources again. The gitignore
+// source files are stamped when the snapshot is written. Gitignore patterns read
+// from Options.Reader cannot be checked for changes and are rejected.
+func (pi *PathIgnore) WriteSnapshot(w io.Writer) error {
+	if pi.opts.GitIgnore != nil && pi.opts.GitIgnore.Reader != nil {
+		return fmt.Errorf("gitignore reader cannot be snapshotted")
+	}
+
+	fp, err := fingerprint(pi.opts)
+	if err != nil {
+		return err
+	}
+	snap := snapshot{Options: fp, Files: map[string]snapshotStamp{}}
+	for _, f := range pi.files() {
+		snap.Files[f] = newSnapshotStamp(pi.opts, f)
+	}
+	for _, m := range pi.strategies {
+		switch m := m.(type) {
+		case *regex.Matcher:
+			s := m.State()
+			snap.Regex = &s
+		case *gitignore.Matcher:
+			s := m.State()
+			snap.GitIgnore = &s
+		case *glob.Matcher:
+			s := m.State()
+			snap.Glob = &s
+		default:
+			return fmt.Errorf("%s matcher cannot be snapshotted", m.Type())
+		}
+	}
+
+	var payload bytes.Buffer
+	if err := gob.NewEncoder(&payload).Encode(snap); err != nil {
+		return fmt.Errorf("encode snapshot: %w", err)
+	}
+	sum := sha256.Sum256(payload.Bytes())
+
+	var buf bytes.Buffer
+	buf.Write(snapshotMagic)
+	buf.Write(binary.BigEndian.AppendUint32(nil, SnapshotVersion))
+	buf.Write(payload.Bytes())
+	buf.Write(sum[:])
+	_, err = w.Write(buf.Bytes())
+	return err
+}
+
+// LoadSnapshot rebuilds a PathIgnore from a snapshot written by WriteSnapshot,
+// without walking and parsing the ignore sources. The RE2 expressions are still
+// compiled, and the source files read to check their stamps. opts must be the
+// options the snapshot was taken with, except for Timeout, Workers and CacheSize
+// which are taken from opts. It returns ErrSnapshotVersion, ErrSnapshotCorrupt or
+// ErrSnapshotStale if the snapshot cannot be used, the PathIgnore should then be
+// built from source with New.
+func LoadSnapshot(r io.Reader, opts Options) (*PathIgnore, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	header := len(snapshotMagic) + 4
+	if len(data) < header+sha256.Size || !bytes.Equal(data[:len(snapshotMagic)], snapshotMagic) {
+		return nil, ErrSnapshotCorrupt
+	}
+	if v := binary.BigEndian.Uint32(data[len(snapshotMagic):header]); v != SnapshotVersion {
+		return nil, fmt.Errorf("%w %d", ErrSnapshotVersion, v)
+	}
+	payload, sum := data[header:len(data)-sha256.Size], data[len(data)-sha256.Size:]
+	if got := sha256.Sum256(payload); !bytes.Equal(got[:], sum) {
+		return nil, fmt.Errorf("%w: checksum mismatch", ErrSnapshotCorrupt)
+	}
+
+	var snap snapshot
+	if err := gob.NewDecoder(bytes.NewReader(payload)).Decode(&snap); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrSnapshotCorrupt, err)
+	}
+
+	opts = opts.normalized()
+	fp, err := fingerprint(opts)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(fp, snap.Options) {
+		return nil, fmt.Errorf("%w: options changed", ErrSnapshotStale)
+	}
+	for f, stamp := range snap.Files {
+		if !newSnapshotStamp(opts, f).equal(stamp) {
+			return nil, fmt.Errorf("%w: %s changed", ErrSnapshotStale, f)
+		}
+	}
+
+	var matchers []match.PathMatcher
+	if snap.Regex != nil {
+		m, err := regex.FromState(*snap.Regex)
+		if err != nil {
+			return nil, fmt.Errorf("regex - %w", err)
+		}
+		matchers = append(matchers, m)
+	}
+	if snap.GitIgnore != nil {
+		m, err := gitignore.FromState(*snap.GitIgnore)
+		if err != nil {
+			return nil, fmt.Errorf("gitignore - %w", err)
+		}
+		matchers = append(matchers, m)
+	}
+	if snap.Glob != nil {
+		m, err := glob.FromState(*snap.Glob)
+		if err != nil {
+			return nil, fmt.Errorf("glob - %w", err)
+		}
+		matchers = append(matchers, m)
+	}
+	if len(matchers) == 0 {
+		return nil, fmt.Errorf("%w: no matching strategy", ErrSnapshotCorrupt)
+	}
+
+	return newPathIgnore(opts, matchers)
+}
+
+// NewFromSnapshot is like New, but loads the rules from the snapshot file if it
+// is usable, see LoadSnapshot. Otherwise the rules are built from source and the
+// snapshot file is replaced with a fresh snapshot. Failing to write the snapshot
+// is not an error, the rules are built from source again next time.
+func NewFromSnapshot(opts Options, file string) (*PathIgnore, error) {
+	if f, err := os.Open(file); err == nil {
+		pi, err := LoadSnapshot(f, opts)
+		f.Close()
+		if err == nil {
+			return pi, nil
+		}
+	}
+
+	pi, err := New(opts)
+	if err != nil {
+		return nil, err
+	}
+	_ = writeSnapshotFile(pi, file)
+	return pi, nil
+}
+
+// writeSnapshotFile replaces file with a snapshot of pi, through a temporary file
+// so that readers never see a partial snapshot.
+func writeSnapshotFile(pi *PathIgnore, file string) error {
+	tmp, err := os.CreateTemp(filepath.Dir(file), filepath.Base(file)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := pi.WriteSnapshot(tmp); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), file)
+}
+
+// fingerprint returns a digest of the options the rules are built from, and of the
+// standard exclude files they resolve to for ExcludeStandard.
+func fingerprint(opts Options) ([]byte, error) {
+	type gitIgnoreKey struct {
+		Patterns        []string
+		FilePath        string
+		ReaderName      string
+		FS              bool
+		Root            string
+		ExcludeStandard bool
+		StandardFiles   []string
+		Engine          gitignore.Engine
+		IgnoreCase      bool
+	}
+	key := struct {
+		Regex      *regex.Options
+		Glob       *glob.Options
+		GitIgnore  *gitIgnoreKey
+		IgnoreCase bool
+		Parallel   bool
+		SinglePass bool
+	}{
+		Regex:      opts.Regex,
+		Glob:       opts.Glob,
+		IgnoreCase: opts.IgnoreCase,
+		Parallel:   opts.Parallel,
+		SinglePass: opts.SinglePass,
+	}
+	if gi := opts.GitIgnore; gi != nil {
+		key.GitIgnore = &gitIgnoreKey{
+			Patterns:        gi.Patterns,
+			FilePath:        gi.FilePath,
+			ReaderName:      gi.ReaderName,
+			FS:              gi.FS != nil,
+			Root:            gi.Root,
+			ExcludeStandard: gi.ExcludeStandard,
+			Engine:          gi.Engine,
+			IgnoreCase:      gi.IgnoreCase,
+		}
+		if gi.ExcludeStandard {
+			files, err := gitignore.StandardFiles(gi.Root)
+			if err != nil {
+				return nil, err
+			}
+			key.GitIgnore.StandardFiles = files
+		}
+	}
+
+	data, err := json.Marshal(key)
+	if err != nil {
+		return nil, fmt.Errorf("fingerprint options: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return sum[:], nil
+}
@@ -0,0 +1,204 @@
+package gopathignore_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	gopathignore "github.com/vbhat161/go-path-ignore"
+	"github.com/vbhat161/go-path-ignore/match/gitignore"
+	"github.com/vbhat161/go-path-ignore/match/glob"
+	"github.com/vbhat161/go-path-ignore/match/regex"
+)
+
+func TestSnapshot(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(root, ".gitignore"), []byte("*.log\n!keep.log\nbuild/\n"), 0o644))
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "src"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "src", ".gitignore"), []byte("gen/\n"), 0o644))
+
+	paths := []string{"main.go", "a.log", "keep.log", "build/", "src/gen/x.go", "gen/x.go", "a.bak", "x.tmp", "docs/x.md"}
+	for _, parallel := range []bool{false, true} {
+		opts := gopathignore.Options{
+			Regex:     &regex.Options{Patterns: []string{`\.bak$`}},
+			GitIgnore: &gitignore.Options{Patterns: []string{"docs/"}, Root: root},
+			Glob:      &glob.Options{Patterns: []string{"*.tmp"}},
+			Parallel:  parallel,
+		}
+		pi, err := gopathignore.New(opts)
+		require.NoError(t, err)
+
+		var buf bytes.Buffer
+		require.NoError(t, pi.WriteSnapshot(&buf))
+		loaded, err := gopathignore.LoadSnapshot(bytes.NewReader(buf.Bytes()), opts)
+		require.NoError(t, err)
+
+		for _, path := range paths {
+			want, err := pi.Match2(context.Background(), path)
+			require.NoError(t, err)
+			got, err := loaded.Match2(context.Background(), path)
+			require.NoError(t, err)
+			require.Equal(t, want.Source(), got.Source(), "parallel %v, path %q", parallel, path)
+			require.Equal(t, want.Type(), got.Type(), path)
+		}
+	}
+}
+
+func TestSnapshotRejected(t *testing.T) {
+	root := t.TempDir()
+	file := filepath.Join(root, ".gitignore")
+	require.NoError(t, os.WriteFile(file, []byte("*.log\n"), 0o644))
+
+	opts := gopathignore.Options{GitIgnore: &gitignore.Options{FilePath: file}}
+	pi, err := gopathignore.New(opts)
+	require.NoError(t, err)
+	var buf bytes.Buffer
+	require.NoError(t, pi.WriteSnapshot(&buf))
+	snap := buf.Bytes()
+
+	load := func(data []byte, opts gopathignore.Options) error {
+		_, err := gopathignore.LoadSnapshot(bytes.NewReader(data), opts)
+		return err
+	}
+	require.NoError(t, load(snap, opts))
+
+	corrupt := bytes.Clone(snap)
+	corrupt[len(corrupt)/2] ^= 0xff
+	require.ErrorIs(t, load(corrupt, opts), gopathignore.ErrSnapshotCorrupt)
+	require.ErrorIs(t, load(snap[:len(snap)-1], opts), gopathignore.ErrSnapshotCorrupt)
+	require.ErrorIs(t, load([]byte("GPIS"), opts), gopathignore.ErrSnapshotCorrupt)
+
+	version := bytes.Clone(snap)
+	version[7]++
+	require.ErrorIs(t, load(version, opts), gopathignore.ErrSnapshotVersion)
+
+	require.ErrorIs(t, load(snap, gopathignore.Options{GitIgnore: &gitignore.Options{FilePath: file}, IgnoreCase: true}),
+		gopathignore.ErrSnapshotStale)
+
+	// Touching the file does not make the snapshot stale, changing its content does,
+	// even when the size and modification time stay the same.
+	info, err := os.Stat(file)
+	require.NoError(t, err)
+	later := info.ModTime().Add(time.Hour)
+	require.NoError(t, os.Chtimes(file, later, later))
+	require.NoError(t, load(snap, opts))
+
+	require.NoError(t, os.WriteFile(file, []byte("*.tmp\n"), 0o644))
+	require.NoError(t, os.Chtimes(file, later, later))
+	require.ErrorIs(t, load(snap, opts), gopathignore.ErrSnapshotStale)
+
+	require.NoError(t, os.WriteFile(file, []byte("*.log\n*.tmp\n"), 0o644))
+	require.ErrorIs(t, load(snap, opts), gopathignore.ErrSnapshotStale)
+}
+
+func TestNewFromSnapshot(t *testing.T) {
+	root := t.TempDir()
+	file := filepath.Join(root, ".gitignore")
+	snapFile := filepath.Join(root, "rules.snapshot")
+	require.NoError(t, os.WriteFile(file, []byte("*.log\n"), 0o644))
+	opts := gopathignore.Options{GitIgnore: &gitignore.Options{FilePath: file}}
+
+	pi, err := gopathignore.NewFromSnapshot(opts, snapFile)
+	require.NoError(t, err)
+	ok, err := pi.Match(context.Background(), "a.log")
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	f, err := os.Open(snapFile)
+	require.NoError(t, err)
+	_, err = gopathignore.LoadSnapshot(f, opts)
+	require.NoError(t, f.Close())
+	require.NoError(t, err)
+
+	// A stale snapshot is rebuilt from source.
+	require.NoError(t, os.WriteFile(file, []byte("*.tmp\n*.bak\n"), 0o644))
+	pi, err = gopathignore.NewFromSnapshot(opts, snapFile)
+	require.NoError(t, err)
+	ok, err = pi.Match(context.Background(), "a.log")
+	require.NoError(t, err)
+	require.False(t, ok)
+	ok, err = pi.Match(context.Background(), "a.tmp")
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	f, err = os.Open(snapFile)
+	require.NoError(t, err)
+	defer f.Close()
+	_, err = gopathignore.LoadSnapshot(f, opts)
+	require.NoError(t, err)
+}
+
+func TestSnapshotExcludesFile(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", "")
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+	t.Setenv("GIT_CONFIG_GLOBAL", "")
+	require.NoError(t, os.MkdirAll(filepath.Join(home, ".config", "git"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(home, ".config", "git", "ignore"), []byte("*.swp\n"), 0o644))
+	other := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(other, "git"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(other, "git", "ignore"), []byte("*.bak\n"), 0o644))
+
+	root := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(root, ".git", "info"), 0o755))
+	opts := gopathignore.Options{GitIgnore: &gitignore.Options{Root: root, ExcludeStandard: true}}
+	pi, err := gopathignore.New(opts)
+	require.NoError(t, err)
+	var buf bytes.Buffer
+	require.NoError(t, pi.WriteSnapshot(&buf))
+	_, err = gopathignore.LoadSnapshot(bytes.NewReader(buf.Bytes()), opts)
+	require.NoError(t, err)
+
+	// The excludes file resolves elsewhere once XDG_CONFIG_HOME is set.
+	t.Setenv("XDG_CONFIG_HOME", other)
+	_, err = gopathignore.LoadSnapshot(bytes.NewReader(buf.Bytes()), opts)
+	require.ErrorIs(t, err, gopathignore.ErrSnapshotStale)
+
+	// So does it when core.excludesFile is set in the repository config.
+	t.Setenv("XDG_CONFIG_HOME", "")
+	require.NoError(t, os.WriteFile(filepath.Join(root, ".git", "config"),
+		[]byte("[core]\n\texcludesFile = "+filepath.Join(other, "git", "ignore")+"\n"), 0o644))
+	_, err = gopathignore.LoadSnapshot(bytes.NewReader(buf.Bytes()), opts)
+	require.ErrorIs(t, err, gopathignore.ErrSnapshotStale)
+}
+
+// BenchmarkSnapshot compares building the rules of a tree from source with New to
+// loading them with LoadSnapshot.
+func BenchmarkSnapshot(b *testing.B) {
+	root := b.TempDir()
+	var lines []byte
+	for i := range 20 {
+		lines = fmt.Appendf(lines, "*.ext%d\n/build%d/\n!keep%d.ext%d\nsrc/**/gen%d/\n", i, i, i, i, i)
+	}
+	for i := range 20 {
+		dir := filepath.Join(root, fmt.Sprintf("pkg%d", i), "sub")
+		require.NoError(b, os.MkdirAll(dir, 0o755))
+		require.NoError(b, os.WriteFile(filepath.Join(dir, ".gitignore"), lines, 0o644))
+	}
+	opts := gopathignore.Options{GitIgnore: &gitignore.Options{Root: root}}
+
+	pi, err := gopathignore.New(opts)
+	require.NoError(b, err)
+	var buf bytes.Buffer
+	require.NoError(b, pi.WriteSnapshot(&buf))
+	snap := buf.Bytes()
+
+	b.Run("New", func(b *testing.B) {
+		for range b.N {
+			_, err := gopathignore.New(opts)
+			require.NoError(b, err)
+		}
+	})
+	b.Run("LoadSnapshot", func(b *testing.B) {
+		for range b.N {
+			_, err := gopathignore.LoadSnapshot(bytes.NewReader(snap), opts)
+			require.NoError(b, err)
+		}
+	})
+}
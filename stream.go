@@ -0,0 +1,97 @@
+package gopathignore
+
+import (
+	"context"
+	"iter"
+
+	"github.com/vbhat161/go-path-ignore/match"
+)
+
+// StreamOptions configure Filter and FilterChan.
+type StreamOptions struct {
+	// SkipIgnored leaves ignored paths out of the stream, so that only paths that
+	// are not ignored are yielded.
+	SkipIgnored bool
+}
+
+// StreamResult is a path matched by Filter or FilterChan. Err is set for the last
+// result of a stream that stopped because ctx was done or matching failed.
+type StreamResult struct {
+	Path string
+	Info match.MatchInfo
+	Err  error
+}
+
+// Filter matches the paths of seq as they are produced and yields each of them
+// with its result, like FilterChan does for a channel. Iteration stops early when
+// ctx is done or matching a path fails, after a last result with Err set. The
+// returned sequence keeps no state, so it may be ranged over again, concurrently.
+func (pi *PathIgnore) Filter(ctx context.Context, seq iter.Seq[string], opts StreamOptions) iter.Seq2[string, StreamResult] {
+	return func(yield func(string, StreamResult) bool) {
+		for path := range seq {
+			if err := ctx.Err(); err != nil {
+				yield(path, StreamResult{Path: path, Err: err})
+				return
+			}
+			res, err := pi.Match2(ctx, path)
+			if err != nil {
+				yield(path, StreamResult{Path: path, Err: err})
+				return
+			}
+			if opts.SkipIgnored && res.Ok() {
+				continue
+			}
+			if !yield(path, StreamResult{Path: path, Info: res}) {
+				return
+			}
+		}
+	}
+}
+
+// FilterChan is like Filter for a channel of paths. The returned channel is
+// unbuffered, so that a slow consumer holds up reading from in. It is closed once
+// in is closed and drained, when ctx is done, or after a result with an error.
+// Cancel ctx to release the goroutine if the results are not read to the end.
+func (pi *PathIgnore) FilterChan(ctx context.Context, in <-chan string, opts StreamOptions) <-chan StreamResult {
+	out := make(chan StreamResult)
+
+	go func() {
+		defer close(out)
+
+		send := func(r StreamResult) bool {
+			select {
+			case out <- r:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		for {
+			var path string
+			select {
+			case <-ctx.Done():
+				return
+			case p, ok := <-in:
+				if !ok {
+					return
+				}
+				path = p
+			}
+
+			res, err := pi.Match2(ctx, path)
+			if err != nil {
+				send(StreamResult{Path: path, Err: err})
+				return
+			}
+			if opts.SkipIgnored && res.Ok() {
+				continue
+			}
+			if !send(StreamResult{Path: path, Info: res}) {
+				return
+			}
+		}
+	}()
+
+	return out
+}
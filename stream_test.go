@@ -0,0 +1,168 @@
+package gopathignore_test
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	gopathignore "github.com/vbhat161/go-path-ignore"
+	"github.com/vbhat161/go-path-ignore/match/gitignore"
+	"go.uber.org/goleak"
+)
+
+func newStreamPathIgnore(t *testing.T) *gopathignore.PathIgnore {
+	t.Helper()
+	pi, err := gopathignore.New(gopathignore.Options{
+		GitIgnore: &gitignore.Options{Patterns: []string{"*.log", "build/"}},
+	})
+	require.NoError(t, err)
+	return pi
+}
+
+func TestFilter(t *testing.T) {
+	pi := newStreamPathIgnore(t)
+	paths := []string{"main.go", "a.log", "build/out.o", "README.md"}
+
+	got := map[string]bool{}
+	var order []string
+	for path, res := range pi.Filter(context.Background(), slices.Values(paths), gopathignore.StreamOptions{}) {
+		require.NoError(t, res.Err)
+		require.Equal(t, path, res.Path)
+		got[path] = res.Info.Ok()
+		order = append(order, path)
+	}
+	require.Equal(t, paths, order)
+	require.Equal(t, map[string]bool{"main.go": false, "a.log": true, "build/out.o": true, "README.md": false}, got)
+
+	var kept []string
+	for path := range pi.Filter(context.Background(), slices.Values(paths), gopathignore.StreamOptions{SkipIgnored: true}) {
+		kept = append(kept, path)
+	}
+	require.Equal(t, []string{"main.go", "README.md"}, kept)
+
+	// Breaking out of the loop stops pulling from the sequence.
+	var pulled []string
+	seq := func(yield func(string) bool) {
+		for _, p := range paths {
+			pulled = append(pulled, p)
+			if !yield(p) {
+				return
+			}
+		}
+	}
+	for range pi.Filter(context.Background(), seq, gopathignore.StreamOptions{}) {
+		break
+	}
+	require.Equal(t, []string{"main.go"}, pulled)
+}
+
+func TestFilterConcurrent(t *testing.T) {
+	pi := newStreamPathIgnore(t)
+	paths := []string{"main.go", "a.log", "build/out.o", "README.md"}
+	filtered := pi.Filter(context.Background(), slices.Values(paths), gopathignore.StreamOptions{SkipIgnored: true})
+
+	var wg sync.WaitGroup
+	kept := make([][]string, 4)
+	for i := range kept {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for path, res := range filtered {
+				if res.Err == nil {
+					kept[i] = append(kept[i], path)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	for _, k := range kept {
+		require.Equal(t, []string{"main.go", "README.md"}, k)
+	}
+}
+
+func TestFilterErr(t *testing.T) {
+	pi := newStreamPathIgnore(t)
+	paths := []string{"main.go", "a.log"}
+
+	collect := func(ctx context.Context, pi *gopathignore.PathIgnore, cancelAfter func()) []gopathignore.StreamResult {
+		var got []gopathignore.StreamResult
+		for _, res := range pi.Filter(ctx, slices.Values(paths), gopathignore.StreamOptions{}) {
+			got = append(got, res)
+			if cancelAfter != nil {
+				cancelAfter()
+			}
+		}
+		return got
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	got := collect(ctx, pi, nil)
+	require.Len(t, got, 1)
+	require.Equal(t, "main.go", got[0].Path)
+	require.ErrorIs(t, got[0].Err, context.Canceled)
+
+	// The stream is cut short by the context of the caller.
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+	got = collect(ctx, pi, cancel)
+	require.Len(t, got, 2)
+	require.Equal(t, "main.go", got[0].Path)
+	require.NoError(t, got[0].Err)
+	require.Equal(t, "a.log", got[1].Path)
+	require.ErrorIs(t, got[1].Err, context.Canceled)
+
+	// Matching fails once the timeout of the rules expires.
+	pi, err := gopathignore.New(gopathignore.Options{
+		GitIgnore: &gitignore.Options{Patterns: []string{"*.log"}},
+		Timeout:   time.Nanosecond,
+	})
+	require.NoError(t, err)
+	got = collect(context.Background(), pi, nil)
+	require.Len(t, got, 1)
+	require.ErrorIs(t, got[0].Err, context.DeadlineExceeded)
+}
+
+func TestFilterChan(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	pi := newStreamPathIgnore(t)
+	in := make(chan string)
+	go func() {
+		defer close(in)
+		for _, p := range []string{"main.go", "a.log", "build/out.o", "README.md"} {
+			in <- p
+		}
+	}()
+
+	var kept []string
+	for res := range pi.FilterChan(context.Background(), in, gopathignore.StreamOptions{SkipIgnored: true}) {
+		require.NoError(t, res.Err)
+		require.False(t, res.Info.Ok())
+		kept = append(kept, res.Path)
+	}
+	require.Equal(t, []string{"main.go", "README.md"}, kept)
+}
+
+func TestFilterChanCancel(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	pi := newStreamPathIgnore(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	in := make(chan string)
+	out := pi.FilterChan(ctx, in, gopathignore.StreamOptions{})
+
+	in <- "a.log"
+	res := <-out
+	require.Equal(t, "a.log", res.Path)
+	require.True(t, res.Info.Ok())
+
+	// The consumer stops reading, canceling releases the pipeline.
+	in <- "b.log"
+	cancel()
+	for range out {
+	}
+}
@@ -0,0 +1,107 @@
+package gopathignore
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/vbhat161/go-path-ignore/match"
+)
+
+// SkipFunc is called by Walk and WalkDir for every ignored entry, with the match
+// that caused it to be skipped. Ignored directories are not descended into. As for
+// fs.WalkDirFunc, a non-nil error stops the walk, fs.SkipAll without an error.
+type SkipFunc func(path string, d fs.DirEntry, info match.MatchInfo) error
+
+// ErrFunc is called for an entry of a walked tree that cannot be read. Returning
+// nil skips the entry and continues the walk, an error stops it.
+type ErrFunc func(path string, err error) error
+
+// WalkDir walks the file tree of fsys rooted at root like fs.WalkDir, but only
+// calls fn for entries that are not ignored. Paths, which are slash separated and
+// relative to fsys, are matched with MatchEntry, and ignored directories are
+// skipped entirely. skip is called for ignored entries and may be nil. Like git,
+// it skips .git directories, see SkipGitDirs.
+func (pi *PathIgnore) WalkDir(ctx context.Context, fsys fs.FS, root string, fn fs.WalkDirFunc, skip SkipFunc) error {
+	return fs.WalkDir(fsys, root, SkipGitDirs(pi.walkDirFunc(ctx, fn, skip, func(p string) string { return p })))
+}
+
+// Walk is like WalkDir for the directory tree at root on the OS file system. The
+// paths given to the matcher are relative to root, while fn and skip receive them
+// joined with root, like filepath.WalkDir does.
+func (pi *PathIgnore) Walk(ctx context.Context, root string, fn fs.WalkDirFunc, skip SkipFunc) error {
+	return fs.WalkDir(os.DirFS(root), ".", SkipGitDirs(pi.walkDirFunc(ctx, fn, skip, func(p string) string {
+		return filepath.Join(root, filepath.FromSlash(p))
+	})))
+}
+
+// SkipGitDirs wraps fn to skip .git directories without calling it for them, as
+// they hold a repository rather than entries of its work tree. The walks of this
+// package all skip them this way.
+func SkipGitDirs(fn fs.WalkDirFunc) fs.WalkDirFunc {
+	return func(path string, d fs.DirEntry, err error) error {
+		if d != nil && d.IsDir() && d.Name() == ".git" {
+			return fs.SkipDir
+		}
+		return fn(path, d, err)
+	}
+}
+
+// walkDirFunc wraps fn to skip ignored entries. osPath converts the fs.FS path of
+// an entry to the path reported to fn and skip.
+func (pi *PathIgnore) walkDirFunc(
+	ctx context.Context,
+	fn fs.WalkDirFunc,
+	skip SkipFunc,
+	osPath func(string) string,
+) fs.WalkDirFunc {
+	return func(path string, d fs.DirEntry, err error) error {
+		// The root itself is not matched, nor entries that could not be read. An
+		// ignored directory is skipped before it is read, so errors reading it are
+		// never reported.
+		if err != nil || path == "." {
+			return fn(osPath(path), d, err)
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		info, err := pi.MatchEntry(ctx, path, d.IsDir())
+		if err != nil {
+			return err
+		}
+		if !info.Ok() {
+			return fn(osPath(path), d, nil)
+		}
+
+		if skip != nil {
+			if err := skip(osPath(path), d, info); err != nil {
+				return err
+			}
+		}
+		if d.IsDir() {
+			return fs.SkipDir
+		}
+		return nil
+	}
+}
+
+// walkTree walks the file tree of fsys rooted at root like fs.WalkDir, calling fn
+// for every entry but "." and skipping .git directories like WalkDir. The errors
+// of entries that cannot be read are passed to onErr, except for the root whose
+// error is returned, as are all errors if onErr is nil.
+func walkTree(fsys fs.FS, root string, onErr ErrFunc, fn func(path string, d fs.DirEntry) error) error {
+	return fs.WalkDir(fsys, root, SkipGitDirs(func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if d == nil || path == root || onErr == nil {
+				return err
+			}
+			return onErr(path, err)
+		}
+		if path == "." {
+			return nil
+		}
+		return fn(path, d)
+	}))
+}
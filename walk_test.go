@@ -0,0 +1,134 @@
+package gopathignore_test
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/require"
+	gopathignore "github.com/vbhat161/go-path-ignore"
+	"github.com/vbhat161/go-path-ignore/match"
+	"github.com/vbhat161/go-path-ignore/match/gitignore"
+	"github.com/vbhat161/go-path-ignore/match/glob"
+)
+
+func TestWalkDir(t *testing.T) {
+	fsys := fstest.MapFS{
+		"main.go":               {},
+		"debug.log":             {},
+		"build/out.o":           {},
+		"build/sub/keep.log":    {},
+		"src/app.go":            {},
+		"src/app.tmp":           {},
+		"src/vendor/lib/x.go":   {},
+		"docs/build/index.md":   {},
+		"docs/readme.md":        {},
+		"node_modules/pkg/a.js": {},
+		".git/HEAD":             {},
+		"docs/.git/config":      {},
+	}
+
+	pi, err := gopathignore.New(gopathignore.Options{
+		GitIgnore: &gitignore.Options{Patterns: []string{"*.log", "!keep.log", "/build/", "vendor/"}},
+		Glob:      &glob.Options{Patterns: []string{"*.tmp", "node_modules"}},
+	})
+	require.NoError(t, err)
+
+	var visited []string
+	skipped := map[string]string{}
+	err = pi.WalkDir(context.Background(), fsys, ".",
+		func(path string, d fs.DirEntry, err error) error {
+			require.NoError(t, err)
+			visited = append(visited, path)
+			return nil
+		},
+		func(path string, d fs.DirEntry, info match.MatchInfo) error {
+			skipped[path] = info.Src()
+			return nil
+		},
+	)
+	require.NoError(t, err)
+
+	require.Equal(t, []string{
+		".",
+		"docs",
+		"docs/build",
+		"docs/build/index.md",
+		"docs/readme.md",
+		"main.go",
+		"src",
+		"src/app.go",
+	}, visited)
+	require.Equal(t, map[string]string{
+		"build":        "/build/",
+		"debug.log":    "*.log",
+		"node_modules": "node_modules",
+		"src/app.tmp":  "*.tmp",
+		"src/vendor":   "vendor/",
+	}, skipped)
+}
+
+func TestWalkDirStop(t *testing.T) {
+	fsys := fstest.MapFS{
+		"a.log": {},
+		"b.go":  {},
+	}
+	pi, err := gopathignore.New(gopathignore.Options{GitIgnore: &gitignore.Options{Patterns: []string{"*.log"}}})
+	require.NoError(t, err)
+
+	var visited []string
+	err = pi.WalkDir(context.Background(), fsys, ".",
+		func(path string, d fs.DirEntry, err error) error {
+			visited = append(visited, path)
+			return nil
+		},
+		func(path string, d fs.DirEntry, info match.MatchInfo) error {
+			return fs.SkipAll
+		},
+	)
+	require.NoError(t, err)
+	require.Equal(t, []string{"."}, visited)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = pi.WalkDir(ctx, fsys, ".", func(string, fs.DirEntry, error) error { return nil }, nil)
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+func TestWalk(t *testing.T) {
+	root := t.TempDir()
+	for _, name := range []string{"a.go", "tmp/x.go", "sub/tmp/y.go", "sub/b.go"} {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o700))
+		require.NoError(t, os.WriteFile(path, nil, 0o600))
+	}
+
+	pi, err := gopathignore.New(gopathignore.Options{GitIgnore: &gitignore.Options{Patterns: []string{"/tmp/"}}})
+	require.NoError(t, err)
+
+	var visited, skipped []string
+	err = pi.Walk(context.Background(), root,
+		func(path string, d fs.DirEntry, err error) error {
+			require.NoError(t, err)
+			visited = append(visited, path)
+			return nil
+		},
+		func(path string, d fs.DirEntry, info match.MatchInfo) error {
+			skipped = append(skipped, path)
+			return nil
+		},
+	)
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		root,
+		filepath.Join(root, "a.go"),
+		filepath.Join(root, "sub"),
+		filepath.Join(root, "sub", "b.go"),
+		filepath.Join(root, "sub", "tmp"),
+		filepath.Join(root, "sub", "tmp", "y.go"),
+	}, visited)
+	require.Equal(t, []string{filepath.Join(root, "tmp")}, skipped)
+}